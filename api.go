@@ -0,0 +1,55 @@
+package irembopay
+
+import (
+	"context"
+	"iter"
+	"net/http"
+	"time"
+)
+
+// InvoiceAPI defines the invoice operations of the IremboPay API
+type InvoiceAPI interface {
+	Create(ctx context.Context, req *InvoiceRequest) (*Invoice, error)
+	CreateWithIdempotency(ctx context.Context, req *InvoiceRequest, idempotencyKey string) (*Invoice, error)
+	CreateWithExpiry(ctx context.Context, req *InvoiceRequest, expiryDuration time.Duration) (*Invoice, error)
+	Get(ctx context.Context, invoiceReference string) (*Invoice, error)
+	List(ctx context.Context, filter *InvoiceFilter) (*InvoicePage, error)
+	ListAll(ctx context.Context, filter *InvoiceFilter) iter.Seq2[*Invoice, error]
+	Update(ctx context.Context, invoiceNumber string, req *UpdateInvoiceRequest) (*Invoice, error)
+	UpdateExpiryTime(ctx context.Context, invoiceNumber string, expiryTime time.Time) (*Invoice, error)
+	Cancel(ctx context.Context, invoiceNumber, reason string) (*Invoice, error)
+	WaitForPayment(ctx context.Context, invoiceNumber string, opts WaitOptions) (*Invoice, error)
+}
+
+// BatchAPI defines the batch invoice operations of the IremboPay API
+type BatchAPI interface {
+	Create(ctx context.Context, req *BatchInvoiceRequest) (*Invoice, error)
+	CreateWithIdempotency(ctx context.Context, req *BatchInvoiceRequest, idempotencyKey string) (*Invoice, error)
+}
+
+// PaymentAPI defines the payment and webhook operations of the IremboPay API
+type PaymentAPI interface {
+	InitiateMomoPayment(ctx context.Context, req *MomoPaymentRequest) (*MomoPaymentResponse, error)
+	InitiateMomoPaymentAutoDetect(ctx context.Context, req *MomoPaymentRequest) (*MomoPaymentResponse, error)
+	VerifyWebhookSignature(signature, payload string) (bool, error)
+	MatchWebhookSignature(signature, payload string) (int, bool, error)
+	ParseNotification(payload string) (*PaymentNotification, error)
+	HandleWebhook(signature, payload string) (*PaymentNotification, error)
+	ReleaseWebhook(ctx context.Context, signature string) error
+	ValidateWebhookTimestamp(signature string, maxAge time.Duration) (bool, error)
+	WebhookHandler(opts WebhookOptions) http.Handler
+}
+
+// CheckoutAPI defines the push-to-pay checkout operations
+type CheckoutAPI interface {
+	PushToPay(ctx context.Context, req *CheckoutRequest) (*Checkout, error)
+	Resume(ctx context.Context, req *CheckoutRequest, checkout *Checkout) (*Checkout, error)
+}
+
+// Ensure the services implement the API interfaces
+var (
+	_ InvoiceAPI  = (*InvoiceService)(nil)
+	_ BatchAPI    = (*BatchService)(nil)
+	_ PaymentAPI  = (*PaymentService)(nil)
+	_ CheckoutAPI = (*CheckoutService)(nil)
+)
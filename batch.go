@@ -22,11 +22,16 @@ func NewBatchService(client *Client, config *Config) *BatchService {
 
 // Create creates a new batch invoice
 func (s *BatchService) Create(ctx context.Context, req *BatchInvoiceRequest) (*Invoice, error) {
+	if err := validateRequest(s.config, req); err != nil {
+		return nil, fmt.Errorf("failed to create batch invoice: %w", err)
+	}
+
 	var invoice Invoice
 	apiReq := Request{
-		Method: http.MethodPost,
-		Path:   "/payments/invoices/batch",
-		Body:   req,
+		Operation: "batch.create",
+		Method:    http.MethodPost,
+		Path:      "/payments/invoices/batch",
+		Body:      req,
 	}
 
 	err := s.client.DoRequest(ctx, apiReq, &invoice)
@@ -0,0 +1,276 @@
+package irembopay
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// CheckoutState represents the progress of a push-to-pay checkout
+type CheckoutState string
+
+const (
+	// CheckoutStatePending is the state of a checkout that has not created its invoice yet
+	CheckoutStatePending CheckoutState = "PENDING"
+	// CheckoutStateInvoiceCreated is the state once the invoice exists
+	CheckoutStateInvoiceCreated CheckoutState = "INVOICE_CREATED"
+	// CheckoutStatePaymentInitiated is the state once the mobile money prompt was sent
+	CheckoutStatePaymentInitiated CheckoutState = "PAYMENT_INITIATED"
+	// CheckoutStatePaid is the final state of a successful checkout
+	CheckoutStatePaid CheckoutState = "PAID"
+	// CheckoutStateFailed is the final state of a checkout that cannot succeed
+	CheckoutStateFailed CheckoutState = "FAILED"
+)
+
+// CheckoutStep identifies the step of a checkout
+type CheckoutStep string
+
+const (
+	// CheckoutStepCreateInvoice creates (or finds) the invoice
+	CheckoutStepCreateInvoice CheckoutStep = "CREATE_INVOICE"
+	// CheckoutStepInitiatePayment sends the mobile money prompt
+	CheckoutStepInitiatePayment CheckoutStep = "INITIATE_PAYMENT"
+	// CheckoutStepAwaitPayment waits for the payment to complete
+	CheckoutStepAwaitPayment CheckoutStep = "AWAIT_PAYMENT"
+)
+
+// CheckoutFailureReason classifies why a checkout step failed
+type CheckoutFailureReason string
+
+const (
+	// CheckoutReasonInvalidRequest means the request was rejected as invalid
+	CheckoutReasonInvalidRequest CheckoutFailureReason = "INVALID_REQUEST"
+	// CheckoutReasonUnauthorized means the secret key was rejected
+	CheckoutReasonUnauthorized CheckoutFailureReason = "UNAUTHORIZED"
+	// CheckoutReasonRejected means the API refused the operation
+	CheckoutReasonRejected CheckoutFailureReason = "REJECTED"
+	// CheckoutReasonInvoiceExpired means the invoice expired before being paid
+	CheckoutReasonInvoiceExpired CheckoutFailureReason = "INVOICE_EXPIRED"
+	// CheckoutReasonInvoiceCancelled means the invoice was cancelled before being paid
+	CheckoutReasonInvoiceCancelled CheckoutFailureReason = "INVOICE_CANCELLED"
+	// CheckoutReasonTransient means a network, rate limit or server error; the checkout can be resumed
+	CheckoutReasonTransient CheckoutFailureReason = "TRANSIENT"
+	// CheckoutReasonCancelled means the context was done; the checkout can be resumed
+	CheckoutReasonCancelled CheckoutFailureReason = "CANCELLED"
+)
+
+// CheckoutError reports the failure of a checkout step
+type CheckoutError struct {
+	Step   CheckoutStep
+	Reason CheckoutFailureReason
+	Err    error
+}
+
+// Error implements the error interface
+func (e *CheckoutError) Error() string {
+	return fmt.Sprintf("checkout failed at %s (%s): %v", e.Step, e.Reason, e.Err)
+}
+
+// Unwrap returns the underlying error
+func (e *CheckoutError) Unwrap() error {
+	return e.Err
+}
+
+// Resumable reports whether the checkout can be resumed after this error
+func (e *CheckoutError) Resumable() bool {
+	return e.Reason == CheckoutReasonTransient || e.Reason == CheckoutReasonCancelled
+}
+
+// CheckoutRequest describes a push-to-pay checkout
+type CheckoutRequest struct {
+	Invoice              *InvoiceRequest // Invoice to create; its TransactionID identifies the checkout
+	AccountIdentifier    string          // Phone number to charge
+	PaymentProvider      PaymentProvider // MTN or AIRTEL, detected from the phone number if empty
+	TransactionReference string          // Optional reference for the payment
+	Wait                 WaitOptions     // Options used while waiting for the payment
+}
+
+// Checkout is the state of a push-to-pay operation. It can be persisted
+// (it encodes to JSON) and passed to CheckoutService.Resume after a failure.
+type Checkout struct {
+	State          CheckoutState         `json:"state"`
+	TransactionID  string                `json:"transactionId"`
+	IdempotencyKey string                `json:"idempotencyKey"`
+	InvoiceNumber  string                `json:"invoiceNumber,omitempty"`
+	Invoice        *Invoice              `json:"invoice,omitempty"`
+	Payment        *MomoPaymentResponse  `json:"payment,omitempty"`
+	FailedStep     CheckoutStep          `json:"failedStep,omitempty"`
+	FailureReason  CheckoutFailureReason `json:"failureReason,omitempty"`
+}
+
+// IsFinal checks if the checkout reached a final state
+func (c *Checkout) IsFinal() bool {
+	return c.State == CheckoutStatePaid || c.State == CheckoutStateFailed
+}
+
+// CheckoutService runs push-to-pay checkouts: create an invoice, initiate a
+// mobile money payment and wait for the payment to complete
+type CheckoutService struct {
+	invoice InvoiceAPI
+	payment PaymentAPI
+}
+
+// NewCheckoutService creates a new checkout service
+func NewCheckoutService(invoice InvoiceAPI, payment PaymentAPI) *CheckoutService {
+	return &CheckoutService{
+		invoice: invoice,
+		payment: payment,
+	}
+}
+
+// PushToPay starts a new checkout and runs it until the payment completes.
+// On error, the returned checkout records its progress and can be resumed.
+func (s *CheckoutService) PushToPay(ctx context.Context, req *CheckoutRequest) (*Checkout, error) {
+	if req == nil || req.Invoice == nil {
+		return nil, &CheckoutError{
+			Step:   CheckoutStepCreateInvoice,
+			Reason: CheckoutReasonInvalidRequest,
+			Err:    fmt.Errorf("invoice request is required"),
+		}
+	}
+
+	checkout := &Checkout{
+		State:          CheckoutStatePending,
+		TransactionID:  req.Invoice.TransactionID,
+		IdempotencyKey: checkoutIdempotencyKey(req.Invoice.TransactionID),
+	}
+	return s.Resume(ctx, req, checkout)
+}
+
+// Resume continues a checkout from its current state. A checkout is never
+// given a second invoice: the invoice is looked up by transaction ID before
+// being created, and created with an idempotency key derived from it.
+func (s *CheckoutService) Resume(ctx context.Context, req *CheckoutRequest, checkout *Checkout) (*Checkout, error) {
+	for !checkout.IsFinal() {
+		var err error
+		switch checkout.State {
+		case CheckoutStatePending:
+			err = s.createInvoice(ctx, req, checkout)
+		case CheckoutStateInvoiceCreated:
+			err = s.initiatePayment(ctx, req, checkout)
+		case CheckoutStatePaymentInitiated:
+			err = s.awaitPayment(ctx, req, checkout)
+		default:
+			return checkout, fmt.Errorf("invalid checkout state: %s", checkout.State)
+		}
+
+		if err != nil {
+			var checkoutErr *CheckoutError
+			if errors.As(err, &checkoutErr) && !checkoutErr.Resumable() {
+				checkout.State = CheckoutStateFailed
+				checkout.FailedStep = checkoutErr.Step
+				checkout.FailureReason = checkoutErr.Reason
+			}
+			return checkout, err
+		}
+	}
+
+	return checkout, nil
+}
+
+// createInvoice finds or creates the invoice of the checkout
+func (s *CheckoutService) createInvoice(ctx context.Context, req *CheckoutRequest, checkout *Checkout) error {
+	if req == nil || req.Invoice == nil {
+		return newCheckoutError(CheckoutStepCreateInvoice, fmt.Errorf("invoice request is required"))
+	}
+	if req.Invoice.TransactionID != checkout.TransactionID {
+		return newCheckoutError(CheckoutStepCreateInvoice,
+			fmt.Errorf("%w: transaction ID does not match the checkout", ErrValidation))
+	}
+
+	// Reuse the invoice if a previous attempt created it
+	invoice, err := s.invoice.Get(ctx, checkout.TransactionID)
+	if err != nil && !IsNotFoundError(err) {
+		return newCheckoutError(CheckoutStepCreateInvoice, err)
+	}
+
+	if invoice == nil {
+		invoiceReq := *req.Invoice
+		invoiceReq.IdempotencyKey = checkout.IdempotencyKey
+		invoice, err = s.invoice.Create(ctx, &invoiceReq)
+		if IsConflictError(err) {
+			// Another attempt created the invoice in the meantime
+			invoice, err = s.invoice.Get(ctx, checkout.TransactionID)
+		}
+		if err != nil {
+			return newCheckoutError(CheckoutStepCreateInvoice, err)
+		}
+	}
+
+	checkout.Invoice = invoice
+	checkout.InvoiceNumber = invoice.InvoiceNumber
+	if invoice.IsCancelled() {
+		return newCheckoutError(CheckoutStepCreateInvoice,
+			fmt.Errorf("invoice %s: %w", invoice.InvoiceNumber, ErrInvoiceCancelled))
+	}
+
+	checkout.State = CheckoutStateInvoiceCreated
+	if invoice.IsPaid() {
+		checkout.State = CheckoutStatePaid
+	}
+	return nil
+}
+
+// initiatePayment sends the mobile money prompt for the invoice
+func (s *CheckoutService) initiatePayment(ctx context.Context, req *CheckoutRequest, checkout *Checkout) error {
+	momoReq := &MomoPaymentRequest{
+		AccountIdentifier:    req.AccountIdentifier,
+		PaymentProvider:      req.PaymentProvider,
+		InvoiceNumber:        checkout.InvoiceNumber,
+		TransactionReference: req.TransactionReference,
+	}
+
+	initiate := s.payment.InitiateMomoPayment
+	if momoReq.PaymentProvider == "" {
+		initiate = s.payment.InitiateMomoPaymentAutoDetect
+	}
+
+	payment, err := initiate(ctx, momoReq)
+	if err != nil {
+		return newCheckoutError(CheckoutStepInitiatePayment, err)
+	}
+
+	checkout.Payment = payment
+	checkout.State = CheckoutStatePaymentInitiated
+	return nil
+}
+
+// awaitPayment waits until the invoice is paid
+func (s *CheckoutService) awaitPayment(ctx context.Context, req *CheckoutRequest, checkout *Checkout) error {
+	invoice, err := s.invoice.WaitForPayment(ctx, checkout.InvoiceNumber, req.Wait)
+	if invoice != nil {
+		checkout.Invoice = invoice
+	}
+	if err != nil {
+		return newCheckoutError(CheckoutStepAwaitPayment, err)
+	}
+
+	checkout.State = CheckoutStatePaid
+	return nil
+}
+
+// newCheckoutError classifies the error of a checkout step
+func newCheckoutError(step CheckoutStep, err error) *CheckoutError {
+	reason := CheckoutReasonRejected
+	switch {
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		reason = CheckoutReasonCancelled
+	case errors.Is(err, ErrInvoiceExpired):
+		reason = CheckoutReasonInvoiceExpired
+	case errors.Is(err, ErrInvoiceCancelled):
+		reason = CheckoutReasonInvoiceCancelled
+	case isTransientError(err):
+		reason = CheckoutReasonTransient
+	case IsValidationError(err), IsBadRequestError(err):
+		reason = CheckoutReasonInvalidRequest
+	case IsUnauthorizedError(err), IsForbiddenError(err):
+		reason = CheckoutReasonUnauthorized
+	}
+
+	return &CheckoutError{Step: step, Reason: reason, Err: err}
+}
+
+// checkoutIdempotencyKey derives a stable idempotency key from a transaction ID
+func checkoutIdempotencyKey(transactionID string) string {
+	return "checkout_" + transactionID
+}
@@ -0,0 +1,175 @@
+package irembopay_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/cruso003/irembopay"
+	"github.com/cruso003/irembopay/irembopaytest"
+)
+
+// checkoutBackend stores the invoice of a checkout behind mocks of the service interfaces
+type checkoutBackend struct {
+	mu      sync.Mutex
+	invoice *irembopay.Invoice
+
+	invoices *irembopaytest.InvoiceAPIMock
+	payments *irembopaytest.PaymentAPIMock
+}
+
+// newCheckoutBackend creates mocks that create, find and pay a single invoice.
+// createErr, if set, is returned by Create after the invoice is created.
+func newCheckoutBackend(createErr error) *checkoutBackend {
+	b := &checkoutBackend{}
+	b.invoices = &irembopaytest.InvoiceAPIMock{
+		CreateFunc: func(ctx context.Context, req *irembopay.InvoiceRequest) (*irembopay.Invoice, error) {
+			b.mu.Lock()
+			defer b.mu.Unlock()
+			if b.invoice != nil {
+				return nil, irembopay.NewIremboPayError(http.StatusConflict, "Duplicate transaction id", "")
+			}
+			b.invoice = &irembopay.Invoice{
+				InvoiceNumber: "880419623157",
+				TransactionID: req.TransactionID,
+				PaymentStatus: irembopay.PaymentStatusNew,
+			}
+			if createErr != nil {
+				return nil, createErr
+			}
+			return b.invoice, nil
+		},
+		GetFunc: func(ctx context.Context, invoiceReference string) (*irembopay.Invoice, error) {
+			b.mu.Lock()
+			defer b.mu.Unlock()
+			if b.invoice == nil || (invoiceReference != b.invoice.TransactionID && invoiceReference != b.invoice.InvoiceNumber) {
+				return nil, irembopay.NewIremboPayError(http.StatusNotFound, "Invoice not found", "")
+			}
+			return b.invoice, nil
+		},
+		WaitForPaymentFunc: func(ctx context.Context, invoiceNumber string, opts irembopay.WaitOptions) (*irembopay.Invoice, error) {
+			return &irembopay.Invoice{InvoiceNumber: invoiceNumber, PaymentStatus: irembopay.PaymentStatusPaid}, nil
+		},
+	}
+	b.payments = &irembopaytest.PaymentAPIMock{
+		InitiateMomoPaymentAutoDetectFunc: func(ctx context.Context, req *irembopay.MomoPaymentRequest) (*irembopay.MomoPaymentResponse, error) {
+			return &irembopay.MomoPaymentResponse{
+				AccountIdentifier: req.AccountIdentifier,
+				PaymentProvider:   irembopay.PaymentProviderMTN,
+				InvoiceNumber:     req.InvoiceNumber,
+				ReferenceID:       "MOMO-00000001",
+			}, nil
+		},
+	}
+	return b
+}
+
+// checkoutRequest returns a checkout request for a new invoice
+func checkoutRequest() *irembopay.CheckoutRequest {
+	return &irembopay.CheckoutRequest{
+		Invoice:           invoiceRequest(),
+		AccountIdentifier: "0781234567",
+	}
+}
+
+func TestCheckoutPushToPay(t *testing.T) {
+	b := newCheckoutBackend(nil)
+	service := irembopay.NewCheckoutService(b.invoices, b.payments)
+
+	checkout, err := service.PushToPay(context.Background(), checkoutRequest())
+	if err != nil {
+		t.Fatalf("PushToPay returned error: %v", err)
+	}
+	if checkout.State != irembopay.CheckoutStatePaid || checkout.InvoiceNumber != "880419623157" {
+		t.Errorf("PushToPay returned state %s for invoice %q, want PAID for 880419623157", checkout.State, checkout.InvoiceNumber)
+	}
+
+	creates := b.invoices.CreateCalls()
+	if len(creates) != 1 || creates[0].Req.IdempotencyKey != checkout.IdempotencyKey || checkout.IdempotencyKey == "" {
+		t.Errorf("Create was called %d times, want once with the idempotency key of the checkout", len(creates))
+	}
+	if payments := b.payments.InitiateMomoPaymentAutoDetectCalls(); len(payments) != 1 || payments[0].Req.InvoiceNumber != "880419623157" {
+		t.Errorf("payment initiated %d times, want once for the invoice", len(payments))
+	}
+}
+
+func TestCheckoutResumeAfterTransientCreateError(t *testing.T) {
+	// The invoice is created, but the response is lost
+	b := newCheckoutBackend(irembopay.NewIremboPayError(http.StatusBadGateway, "Bad Gateway", ""))
+	service := irembopay.NewCheckoutService(b.invoices, b.payments)
+	req := checkoutRequest()
+
+	checkout, err := service.PushToPay(context.Background(), req)
+	var checkoutErr *irembopay.CheckoutError
+	if !errors.As(err, &checkoutErr) || !checkoutErr.Resumable() || checkoutErr.Step != irembopay.CheckoutStepCreateInvoice {
+		t.Fatalf("PushToPay returned %v, want a resumable error at %s", err, irembopay.CheckoutStepCreateInvoice)
+	}
+	if checkout.State != irembopay.CheckoutStatePending {
+		t.Fatalf("PushToPay left the checkout in state %s, want PENDING", checkout.State)
+	}
+
+	// Resuming finds the invoice instead of creating a second one
+	checkout, err = service.Resume(context.Background(), req, checkout)
+	if err != nil {
+		t.Fatalf("Resume returned error: %v", err)
+	}
+	if checkout.State != irembopay.CheckoutStatePaid || checkout.InvoiceNumber != "880419623157" {
+		t.Errorf("Resume returned state %s for invoice %q, want PAID for 880419623157", checkout.State, checkout.InvoiceNumber)
+	}
+	if creates := b.invoices.CreateCalls(); len(creates) != 1 {
+		t.Errorf("Create was called %d times, want once", len(creates))
+	}
+}
+
+func TestCheckoutFallsBackToGetOnConflict(t *testing.T) {
+	b := newCheckoutBackend(nil)
+
+	// Another attempt creates the invoice between the lookup and the creation
+	get := b.invoices.GetFunc
+	b.invoices.GetFunc = func(ctx context.Context, invoiceReference string) (*irembopay.Invoice, error) {
+		if len(b.invoices.GetCalls()) == 1 {
+			b.invoices.CreateFunc(ctx, invoiceRequest())
+			return nil, irembopay.NewIremboPayError(http.StatusNotFound, "Invoice not found", "")
+		}
+		return get(ctx, invoiceReference)
+	}
+	service := irembopay.NewCheckoutService(b.invoices, b.payments)
+
+	checkout, err := service.PushToPay(context.Background(), checkoutRequest())
+	if err != nil {
+		t.Fatalf("PushToPay returned error: %v", err)
+	}
+	if checkout.State != irembopay.CheckoutStatePaid || checkout.InvoiceNumber != "880419623157" {
+		t.Errorf("PushToPay returned state %s for invoice %q, want PAID for 880419623157", checkout.State, checkout.InvoiceNumber)
+	}
+	if creates := b.invoices.CreateCalls(); len(creates) != 1 {
+		t.Errorf("Create was called %d times through the checkout, want once", len(creates))
+	}
+	if gets := b.invoices.GetCalls(); len(gets) != 2 || gets[1].InvoiceReference != "TST-001" {
+		t.Errorf("Get was called %d times, want a second lookup by transaction ID after the conflict", len(gets))
+	}
+}
+
+func TestCheckoutFailsOnCancelledInvoice(t *testing.T) {
+	b := newCheckoutBackend(nil)
+	b.invoice = &irembopay.Invoice{
+		InvoiceNumber: "880419623157",
+		TransactionID: "TST-001",
+		PaymentStatus: irembopay.PaymentStatusCancelled,
+	}
+	service := irembopay.NewCheckoutService(b.invoices, b.payments)
+
+	checkout, err := service.PushToPay(context.Background(), checkoutRequest())
+	if !errors.Is(err, irembopay.ErrInvoiceCancelled) {
+		t.Fatalf("PushToPay returned %v, want ErrInvoiceCancelled", err)
+	}
+	if checkout.State != irembopay.CheckoutStateFailed || checkout.FailureReason != irembopay.CheckoutReasonInvoiceCancelled {
+		t.Errorf("PushToPay returned state %s (%s), want FAILED (%s)",
+			checkout.State, checkout.FailureReason, irembopay.CheckoutReasonInvoiceCancelled)
+	}
+	if creates := b.invoices.CreateCalls(); len(creates) != 0 {
+		t.Errorf("Create was called %d times, want none", len(creates))
+	}
+}
@@ -0,0 +1,317 @@
+package irembopay
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// CircuitState is the state of a circuit breaker
+type CircuitState string
+
+const (
+	// CircuitClosed lets all requests through
+	CircuitClosed CircuitState = "closed"
+	// CircuitOpen rejects all requests with ErrCircuitOpen
+	CircuitOpen CircuitState = "open"
+	// CircuitHalfOpen lets a limited number of trial requests through
+	CircuitHalfOpen CircuitState = "half-open"
+)
+
+// CircuitBreakerPolicy controls when the circuit breaker stops sending
+// requests to the API. Network errors, timeouts and 5xx responses count as
+// failures; other responses count as successes.
+type CircuitBreakerPolicy struct {
+	FailureRatio     float64       // Ratio of failed requests in the window that opens the circuit (0 to 1)
+	MinRequests      int           // Requests in the window before the ratio is considered
+	Window           time.Duration // Period over which requests are counted in the closed state
+	OpenTimeout      time.Duration // Time the circuit stays open before trial requests are allowed
+	HalfOpenRequests int           // Successful trial requests needed to close the circuit
+
+	// PerOperation keeps a separate circuit for each operation (invoice.create,
+	// payment.initiate_momo, ...) instead of one for the whole client
+	PerOperation bool
+
+	// OnStateChange is called after a circuit changes state. The operation is
+	// empty when the circuit is shared by the whole client.
+	OnStateChange func(operation string, from, to CircuitState)
+
+	// Now returns the current time (default: time.Now). Tests can replace it
+	// to control the windows and timeouts.
+	Now func() time.Time
+}
+
+// DefaultCircuitBreakerPolicy returns a circuit breaker policy opening the
+// circuit when half of at least 10 requests fail within a minute, and trying
+// again after 30 seconds
+func DefaultCircuitBreakerPolicy() CircuitBreakerPolicy {
+	return CircuitBreakerPolicy{
+		FailureRatio:     0.5,
+		MinRequests:      10,
+		Window:           time.Minute,
+		OpenTimeout:      30 * time.Second,
+		HalfOpenRequests: 1,
+	}
+}
+
+// Validate checks if the circuit breaker policy is valid
+func (p CircuitBreakerPolicy) Validate() error {
+	if p.FailureRatio <= 0 || p.FailureRatio > 1 {
+		return fmt.Errorf("circuit breaker failure ratio must be greater than 0 and at most 1")
+	}
+	if p.MinRequests < 1 {
+		return fmt.Errorf("circuit breaker min requests must be at least 1")
+	}
+	if p.Window <= 0 || p.OpenTimeout <= 0 {
+		return fmt.Errorf("circuit breaker window and open timeout must be positive")
+	}
+	if p.HalfOpenRequests < 1 {
+		return fmt.Errorf("circuit breaker half-open requests must be at least 1")
+	}
+	return nil
+}
+
+// now returns the current time according to the policy
+func (p *CircuitBreakerPolicy) now() time.Time {
+	if p.Now != nil {
+		return p.Now()
+	}
+	return time.Now()
+}
+
+// CircuitOpenError is returned when a request is rejected by an open circuit
+// breaker without being sent
+type CircuitOpenError struct {
+	Operation string    // Operation of the circuit, empty for the client-wide circuit
+	RetryAt   time.Time // Time at which trial requests will be allowed
+}
+
+// Error implements the error interface
+func (e *CircuitOpenError) Error() string {
+	if e.Operation == "" {
+		return fmt.Sprintf("circuit breaker open until %s", e.RetryAt.Format(time.RFC3339))
+	}
+	return fmt.Sprintf("circuit breaker open for %s until %s", e.Operation, e.RetryAt.Format(time.RFC3339))
+}
+
+// Is reports whether the error matches ErrCircuitOpen
+func (e *CircuitOpenError) Is(target error) bool {
+	return target == ErrCircuitOpen
+}
+
+// circuit tracks the state of a single circuit
+type circuit struct {
+	state      CircuitState
+	generation uint64    // Incremented on every state change, to ignore stale results
+	expiry     time.Time // End of the window when closed, end of the timeout when open
+	requests   int       // Requests counted in the closed window
+	failures   int       // Failures counted in the closed window
+	trials     int       // Trial requests in flight or completed when half-open
+	successes  int       // Successful trial requests when half-open
+}
+
+// stateChange is a state change to report once the lock is released
+type stateChange struct {
+	operation string
+	from, to  CircuitState
+}
+
+// circuitBreaker fails requests fast while the API is failing
+type circuitBreaker struct {
+	mu       sync.Mutex
+	policy   CircuitBreakerPolicy
+	logger   *slog.Logger
+	circuits map[string]*circuit
+}
+
+// newCircuitBreaker creates a circuit breaker with all circuits closed
+func newCircuitBreaker(policy CircuitBreakerPolicy, logger *slog.Logger) *circuitBreaker {
+	return &circuitBreaker{
+		policy:   policy,
+		logger:   logger,
+		circuits: make(map[string]*circuit),
+	}
+}
+
+// key returns the key of the circuit of an operation
+func (b *circuitBreaker) key(operation string) string {
+	if b.policy.PerOperation {
+		return operation
+	}
+	return ""
+}
+
+// circuit returns the circuit of a key, updating its state for the current time
+func (b *circuitBreaker) circuit(key string, now time.Time, changes *[]stateChange) *circuit {
+	c, ok := b.circuits[key]
+	if !ok {
+		c = &circuit{state: CircuitClosed, expiry: now.Add(b.policy.Window)}
+		b.circuits[key] = c
+	}
+
+	switch {
+	case c.state == CircuitClosed && !now.Before(c.expiry):
+		// Start a new window
+		c.requests, c.failures = 0, 0
+		c.expiry = now.Add(b.policy.Window)
+	case c.state == CircuitOpen && !now.Before(c.expiry):
+		b.setState(key, c, CircuitHalfOpen, now, changes)
+	}
+	return c
+}
+
+// setState moves a circuit to a new state and records the change
+func (b *circuitBreaker) setState(key string, c *circuit, state CircuitState, now time.Time, changes *[]stateChange) {
+	*changes = append(*changes, stateChange{operation: key, from: c.state, to: state})
+
+	c.state = state
+	c.generation++
+	c.requests, c.failures, c.trials, c.successes = 0, 0, 0, 0
+	switch state {
+	case CircuitClosed:
+		c.expiry = now.Add(b.policy.Window)
+	case CircuitOpen:
+		c.expiry = now.Add(b.policy.OpenTimeout)
+	}
+}
+
+// allow checks if a request of the operation may be sent. It returns the
+// generation of the circuit, to be passed to done with the result.
+func (b *circuitBreaker) allow(operation string) (uint64, error) {
+	var changes []stateChange
+	defer func() { b.notify(changes) }()
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	key := b.key(operation)
+	c := b.circuit(key, b.policy.now(), &changes)
+	switch c.state {
+	case CircuitOpen:
+		return 0, &CircuitOpenError{Operation: key, RetryAt: c.expiry}
+	case CircuitHalfOpen:
+		if c.trials >= b.policy.HalfOpenRequests {
+			return 0, &CircuitOpenError{Operation: key, RetryAt: b.policy.now()}
+		}
+		c.trials++
+	}
+	return c.generation, nil
+}
+
+// done records the result of a request allowed in the given generation
+func (b *circuitBreaker) done(operation string, generation uint64, failed bool) {
+	var changes []stateChange
+	defer func() { b.notify(changes) }()
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	key := b.key(operation)
+	now := b.policy.now()
+	c := b.circuit(key, now, &changes)
+	if c.generation != generation {
+		// The circuit changed state while the request was in flight
+		return
+	}
+
+	switch c.state {
+	case CircuitClosed:
+		c.requests++
+		if failed {
+			c.failures++
+		}
+		if c.requests >= b.policy.MinRequests &&
+			float64(c.failures) >= b.policy.FailureRatio*float64(c.requests) {
+			b.setState(key, c, CircuitOpen, now, &changes)
+		}
+	case CircuitHalfOpen:
+		if failed {
+			b.setState(key, c, CircuitOpen, now, &changes)
+			return
+		}
+		c.successes++
+		if c.successes >= b.policy.HalfOpenRequests {
+			b.setState(key, c, CircuitClosed, now, &changes)
+		}
+	}
+}
+
+// abandon gives back the trial of a request allowed in the given generation
+// whose result must not be counted
+func (b *circuitBreaker) abandon(operation string, generation uint64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	c, ok := b.circuits[b.key(operation)]
+	if ok && c.generation == generation && c.state == CircuitHalfOpen && c.trials > 0 {
+		c.trials--
+	}
+}
+
+// state returns the current state of the circuit of an operation
+func (b *circuitBreaker) state(operation string) CircuitState {
+	var changes []stateChange
+	defer func() { b.notify(changes) }()
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.circuit(b.key(operation), b.policy.now(), &changes).state
+}
+
+// notify reports state changes to the logger and the callback
+func (b *circuitBreaker) notify(changes []stateChange) {
+	for _, change := range changes {
+		if b.logger != nil {
+			level := slog.LevelInfo
+			if change.to == CircuitOpen {
+				level = slog.LevelWarn
+			}
+			b.logger.Log(context.Background(), level, "irembopay circuit breaker state changed",
+				slog.String("operation", change.operation),
+				slog.String("from", string(change.from)),
+				slog.String("to", string(change.to)))
+		}
+		if b.policy.OnStateChange != nil {
+			b.policy.OnStateChange(change.operation, change.from, change.to)
+		}
+	}
+}
+
+// guard checks the circuit breaker before an attempt of the request, and
+// returns the function recording the result of the attempt
+func (c *Client) guard(ctx context.Context, req Request) (func(*http.Response, error), error) {
+	if c.breaker == nil {
+		return func(*http.Response, error) {}, nil
+	}
+
+	operation := req.operation()
+	generation, err := c.breaker.allow(operation)
+	if err != nil {
+		return nil, err
+	}
+	return func(resp *http.Response, err error) {
+		switch {
+		case err != nil && errors.Is(ctx.Err(), context.Canceled):
+			// Cancelled by the caller, which says nothing about the API
+			c.breaker.abandon(operation, generation)
+		case err != nil, resp.StatusCode >= 500:
+			c.breaker.done(operation, generation, true)
+		default:
+			c.breaker.done(operation, generation, false)
+		}
+	}, nil
+}
+
+// CircuitState returns the state of the circuit breaker for an operation,
+// such as invoice.create. The operation is ignored when the circuit is shared
+// by the whole client. It returns CircuitClosed without a circuit breaker.
+func (c *Client) CircuitState(operation string) CircuitState {
+	if c.breaker == nil {
+		return CircuitClosed
+	}
+	return c.breaker.state(operation)
+}
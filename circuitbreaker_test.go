@@ -0,0 +1,299 @@
+package irembopay_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/cruso003/irembopay"
+)
+
+// fakeClock is a manually advanced clock
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+// breakerTest is a client with a circuit breaker in front of a server whose
+// status code can be changed
+type breakerTest struct {
+	client  *irembopay.IremboPay
+	clock   *fakeClock
+	status  atomic.Int32
+	sent    atomic.Int32
+	mu      sync.Mutex
+	changes []string
+}
+
+// newBreakerTest creates a breaker test; configure adjusts the default policy
+func newBreakerTest(t *testing.T, configure func(*irembopay.CircuitBreakerPolicy), opts ...irembopay.ConfigOption) *breakerTest {
+	t.Helper()
+
+	bt := &breakerTest{clock: &fakeClock{now: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)}}
+	bt.status.Store(http.StatusOK)
+
+	policy := irembopay.DefaultCircuitBreakerPolicy()
+	policy.Now = bt.clock.Now
+	policy.OnStateChange = func(operation string, from, to irembopay.CircuitState) {
+		bt.mu.Lock()
+		defer bt.mu.Unlock()
+		bt.changes = append(bt.changes, operation+":"+string(from)+"->"+string(to))
+	}
+	if configure != nil {
+		configure(&policy)
+	}
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		bt.sent.Add(1)
+		status := int(bt.status.Load())
+		if status == http.StatusOK {
+			writeJSON(w, status, invoiceResponse)
+			return
+		}
+		writeJSON(w, status, `{"success":false,"message":"failed"}`)
+	})
+	bt.client = newTestClient(t, handler, append(opts, irembopay.WithCircuitBreaker(policy))...)
+	return bt
+}
+
+// get gets an invoice, expecting the server to answer with the status code
+func (bt *breakerTest) get(ctx context.Context, status int) error {
+	bt.status.Store(int32(status))
+	_, err := bt.client.Invoice.Get(ctx, "880419623157")
+	return err
+}
+
+// stateChanges returns the state changes reported so far
+func (bt *breakerTest) stateChanges() []string {
+	bt.mu.Lock()
+	defer bt.mu.Unlock()
+	return append([]string(nil), bt.changes...)
+}
+
+func TestCircuitBreakerOpensOnFailureRatio(t *testing.T) {
+	bt := newBreakerTest(t, func(p *irembopay.CircuitBreakerPolicy) { p.MinRequests = 4 })
+	ctx := context.Background()
+
+	// 4xx responses count as successes: the API is up
+	bt.get(ctx, http.StatusOK)
+	bt.get(ctx, http.StatusNotFound)
+	bt.get(ctx, http.StatusServiceUnavailable)
+	if state := bt.client.CircuitState("invoice.get"); state != irembopay.CircuitClosed {
+		t.Fatalf("state after 1 failure in 3 requests = %s, want closed", state)
+	}
+
+	bt.get(ctx, http.StatusServiceUnavailable)
+	if state := bt.client.CircuitState("invoice.get"); state != irembopay.CircuitOpen {
+		t.Fatalf("state after 2 failures in 4 requests = %s, want open", state)
+	}
+
+	err := bt.get(ctx, http.StatusOK)
+	if !irembopay.IsCircuitOpenError(err) {
+		t.Fatalf("Get returned %v, want ErrCircuitOpen", err)
+	}
+	var openErr *irembopay.CircuitOpenError
+	if !errors.As(err, &openErr) {
+		t.Fatalf("Get returned %v, want a *CircuitOpenError", err)
+	}
+	if want := bt.clock.Now().Add(30 * time.Second); !openErr.RetryAt.Equal(want) {
+		t.Errorf("RetryAt = %v, want %v", openErr.RetryAt, want)
+	}
+	if got := irembopay.ErrorClass(err); got != "circuit_open" {
+		t.Errorf("ErrorClass = %q, want circuit_open", got)
+	}
+	if got := bt.sent.Load(); got != 4 {
+		t.Errorf("server received %d requests, want 4", got)
+	}
+	if got, want := bt.stateChanges(), []string{":closed->open"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("state changes = %v, want %v", got, want)
+	}
+}
+
+func TestCircuitBreakerWindowResetsCounts(t *testing.T) {
+	bt := newBreakerTest(t, func(p *irembopay.CircuitBreakerPolicy) { p.MinRequests = 2 })
+	ctx := context.Background()
+
+	bt.get(ctx, http.StatusServiceUnavailable)
+	bt.clock.Advance(time.Minute)
+	bt.get(ctx, http.StatusServiceUnavailable)
+
+	if state := bt.client.CircuitState("invoice.get"); state != irembopay.CircuitClosed {
+		t.Errorf("state after failures in separate windows = %s, want closed", state)
+	}
+}
+
+func TestCircuitBreakerHalfOpen(t *testing.T) {
+	bt := newBreakerTest(t, func(p *irembopay.CircuitBreakerPolicy) { p.MinRequests = 1 })
+	ctx := context.Background()
+
+	bt.get(ctx, http.StatusServiceUnavailable)
+	bt.clock.Advance(29 * time.Second)
+	if state := bt.client.CircuitState("invoice.get"); state != irembopay.CircuitOpen {
+		t.Fatalf("state before the open timeout = %s, want open", state)
+	}
+
+	bt.clock.Advance(time.Second)
+	if state := bt.client.CircuitState("invoice.get"); state != irembopay.CircuitHalfOpen {
+		t.Fatalf("state after the open timeout = %s, want half-open", state)
+	}
+
+	// A failed trial opens the circuit again
+	if err := bt.get(ctx, http.StatusBadGateway); !irembopay.IsServerError(err) {
+		t.Fatalf("trial returned %v, want a server error", err)
+	}
+	if state := bt.client.CircuitState("invoice.get"); state != irembopay.CircuitOpen {
+		t.Fatalf("state after a failed trial = %s, want open", state)
+	}
+
+	// A successful trial closes it
+	bt.clock.Advance(30 * time.Second)
+	if err := bt.get(ctx, http.StatusOK); err != nil {
+		t.Fatalf("trial returned error: %v", err)
+	}
+	if state := bt.client.CircuitState("invoice.get"); state != irembopay.CircuitClosed {
+		t.Fatalf("state after a successful trial = %s, want closed", state)
+	}
+
+	want := []string{
+		":closed->open",
+		":open->half-open",
+		":half-open->open",
+		":open->half-open",
+		":half-open->closed",
+	}
+	if got := bt.stateChanges(); !reflect.DeepEqual(got, want) {
+		t.Errorf("state changes = %v, want %v", got, want)
+	}
+}
+
+func TestCircuitBreakerPerOperation(t *testing.T) {
+	bt := newBreakerTest(t, func(p *irembopay.CircuitBreakerPolicy) {
+		p.MinRequests = 1
+		p.PerOperation = true
+	})
+	ctx := context.Background()
+
+	bt.get(ctx, http.StatusServiceUnavailable)
+	if state := bt.client.CircuitState("invoice.get"); state != irembopay.CircuitOpen {
+		t.Fatalf("invoice.get state = %s, want open", state)
+	}
+	if state := bt.client.CircuitState("invoice.list"); state != irembopay.CircuitClosed {
+		t.Errorf("invoice.list state = %s, want closed", state)
+	}
+
+	bt.status.Store(http.StatusOK)
+	if _, err := bt.client.Invoice.Update(ctx, "880419623157", &irembopay.UpdateInvoiceRequest{
+		ExpiryAt: irembopay.FormatTime(time.Now().Add(time.Hour)),
+	}); irembopay.IsCircuitOpenError(err) {
+		t.Errorf("Update was rejected by the invoice.get circuit: %v", err)
+	}
+	if got, want := bt.stateChanges(), []string{"invoice.get:closed->open"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("state changes = %v, want %v", got, want)
+	}
+}
+
+func TestCircuitBreakerIgnoresCancellation(t *testing.T) {
+	var blocking atomic.Bool
+	bt := newBreakerTest(t, func(p *irembopay.CircuitBreakerPolicy) { p.MinRequests = 1 },
+		irembopay.WithMiddleware(func(next http.RoundTripper) http.RoundTripper {
+			return irembopay.RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+				if blocking.Load() {
+					// Hang until the caller gives up
+					<-req.Context().Done()
+					return nil, req.Context().Err()
+				}
+				return next.RoundTrip(req)
+			})
+		}))
+
+	cancelled := func() error {
+		blocking.Store(true)
+		defer blocking.Store(false)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		time.AfterFunc(20*time.Millisecond, cancel)
+		return bt.get(ctx, http.StatusOK)
+	}
+
+	if err := cancelled(); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Get returned %v, want context.Canceled", err)
+	}
+	if state := bt.client.CircuitState("invoice.get"); state != irembopay.CircuitClosed {
+		t.Fatalf("state after a cancelled request = %s, want closed", state)
+	}
+
+	// A cancelled trial gives its slot back to the next request
+	bt.get(context.Background(), http.StatusServiceUnavailable)
+	bt.clock.Advance(30 * time.Second)
+	if err := cancelled(); !errors.Is(err, context.Canceled) {
+		t.Fatalf("trial returned %v, want context.Canceled", err)
+	}
+	if err := bt.get(context.Background(), http.StatusOK); err != nil {
+		t.Fatalf("trial after a cancelled trial returned error: %v", err)
+	}
+	if state := bt.client.CircuitState("invoice.get"); state != irembopay.CircuitClosed {
+		t.Errorf("state after a successful trial = %s, want closed", state)
+	}
+}
+
+func TestCircuitBreakerIgnoresRateLimiterWaits(t *testing.T) {
+	bt := newBreakerTest(t, func(p *irembopay.CircuitBreakerPolicy) { p.MinRequests = 2 },
+		irembopay.WithRateLimit(1, 1))
+
+	if err := bt.get(context.Background(), http.StatusOK); err != nil {
+		t.Fatalf("Get returned error: %v", err)
+	}
+
+	// The second request times out waiting for the limiter, without being sent
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := bt.get(ctx, http.StatusOK); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Get returned %v, want context.DeadlineExceeded", err)
+	}
+
+	if state := bt.client.CircuitState("invoice.get"); state != irembopay.CircuitClosed {
+		t.Errorf("state after a rate limiter timeout = %s, want closed", state)
+	}
+	if got := bt.stateChanges(); len(got) != 0 {
+		t.Errorf("state changes = %v, want none", got)
+	}
+}
+
+func TestCircuitBreakerTrialIsNotHeldByRateLimiterWaits(t *testing.T) {
+	bt := newBreakerTest(t, func(p *irembopay.CircuitBreakerPolicy) { p.MinRequests = 1 },
+		irembopay.WithRateLimit(20, 1))
+
+	bt.get(context.Background(), http.StatusServiceUnavailable)
+	bt.clock.Advance(30 * time.Second)
+
+	// Times out in the limiter, which refills a token every 50ms
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+	if err := bt.get(ctx, http.StatusOK); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Get returned %v, want context.DeadlineExceeded", err)
+	}
+
+	if err := bt.get(context.Background(), http.StatusOK); err != nil {
+		t.Fatalf("trial returned error: %v", err)
+	}
+	if state := bt.client.CircuitState("invoice.get"); state != irembopay.CircuitClosed {
+		t.Errorf("state after a successful trial = %s, want closed", state)
+	}
+}
@@ -14,16 +14,50 @@ import (
 type Client struct {
 	config     *Config
 	httpClient *http.Client
+	metrics    requestMetrics
+	limiter    *rateLimiter    // Shared rate limiter (nil without a rate limit)
+	slots      chan struct{}   // Concurrency slots (nil without a concurrency cap)
+	breaker    *circuitBreaker // Circuit breaker (nil when disabled)
 }
 
 // NewClient creates a new IremboPay API client
 func NewClient(config *Config) *Client {
-	return &Client{
-		config: config,
-		httpClient: &http.Client{
-			Timeout: 30 * time.Second,
-		},
+	c := &Client{
+		config:     config,
+		httpClient: newHTTPClient(config),
+		metrics:    newRequestMetrics(config.meter()),
 	}
+	if config.RateLimit > 0 {
+		c.limiter = newRateLimiter(config.RateLimit, config.RateBurst)
+	}
+	if config.MaxConcurrency > 0 {
+		c.slots = make(chan struct{}, config.MaxConcurrency)
+	}
+	if config.CircuitBreaker != nil {
+		c.breaker = newCircuitBreaker(*config.CircuitBreaker, config.Logger)
+	}
+	return c
+}
+
+// newHTTPClient builds the HTTP client from the configuration
+func newHTTPClient(config *Config) *http.Client {
+	httpClient := &http.Client{
+		Timeout: 30 * time.Second,
+	}
+	if config.HTTPClient != nil {
+		// Copy the client so that the caller's transport is left untouched
+		copied := *config.HTTPClient
+		httpClient = &copied
+	}
+
+	if config.Timeout > 0 {
+		httpClient.Timeout = config.Timeout
+	}
+	if len(config.Middleware) > 0 {
+		httpClient.Transport = chainMiddleware(httpClient.Transport, config.Middleware)
+	}
+
+	return httpClient
 }
 
 // Request represents an HTTP request to the API
@@ -33,11 +67,59 @@ type Request struct {
 	Body    interface{}
 	Headers map[string]string
 	Params  map[string]string
+
+	// Operation names the call in traces and metrics, e.g. invoice.create
+	// (default: the method and path)
+	Operation string
+}
+
+// operation returns the name of the call used in traces and metrics
+func (r Request) operation() string {
+	if r.Operation != "" {
+		return r.Operation
+	}
+	return r.Method + " " + r.Path
 }
 
-// DoRequest performs an HTTP request and decodes the response
+// callInfo describes how an API call was performed
+type callInfo struct {
+	attempts int            // Number of attempts made
+	resp     *http.Response // Response of the last attempt, if any
+	body     []byte         // Body of the response of the last attempt
+}
+
+// DoRequest performs an HTTP request and decodes the response. The call is
+// traced and measured with the configured tracer and meter.
 func (c *Client) DoRequest(ctx context.Context, req Request, result interface{}) error {
-	var bodyReader io.Reader
+	attrs := []Attribute{
+		{Key: AttrOperation, Value: req.operation()},
+		{Key: AttrMethod, Value: req.Method},
+	}
+	ctx, span := c.config.tracer().Start(ctx, "irembopay "+req.operation(), attrs...)
+	defer span.End()
+
+	start := time.Now()
+	var info callInfo
+	err := c.doRequest(ctx, req, result, &info)
+
+	if info.resp != nil {
+		attrs = append(attrs, Attribute{Key: AttrStatusCode, Value: info.resp.StatusCode})
+	}
+	if err != nil {
+		attrs = append(attrs, Attribute{Key: AttrErrorType, Value: ErrorClass(err)})
+		span.RecordError(err)
+	}
+	latency := time.Since(start)
+	captureResponse(ctx, &info, latency)
+	c.metrics.record(ctx, latency, attrs)
+	span.SetAttributes(append(attrs, Attribute{Key: AttrAttempts, Value: info.attempts})...)
+
+	return err
+}
+
+// doRequest performs an HTTP request and decodes the response, recording how it was performed
+func (c *Client) doRequest(ctx context.Context, req Request, result interface{}, info *callInfo) error {
+	var bodyBytes []byte
 	if req.Body != nil {
 		// Check if the body has an idempotency key
 		if invoiceReq, ok := req.Body.(*InvoiceRequest); ok && invoiceReq.IdempotencyKey != "" {
@@ -52,49 +134,16 @@ func (c *Client) DoRequest(ctx context.Context, req Request, result interface{})
 			req.Headers["X-Idempotency-Key"] = batchReq.IdempotencyKey
 		}
 
-		bodyBytes, err := json.Marshal(req.Body)
+		var err error
+		bodyBytes, err = json.Marshal(req.Body)
 		if err != nil {
 			return fmt.Errorf("error marshaling request body: %w", err)
 		}
-		bodyReader = bytes.NewBuffer(bodyBytes)
 	}
 
-	url := fmt.Sprintf("https://%s%s", c.config.Host, req.Path)
-	httpReq, err := http.NewRequestWithContext(ctx, req.Method, url, bodyReader)
+	resp, body, err := c.send(ctx, req, bodyBytes, info)
 	if err != nil {
-		return fmt.Errorf("error creating HTTP request: %w", err)
-	}
-
-	// Set default headers
-	httpReq.Header.Set("Content-Type", "application/json")
-	httpReq.Header.Set("Accept", "application/json")
-	httpReq.Header.Set("irembopay-secretKey", c.config.SecretKey)
-	httpReq.Header.Set("X-API-Version", c.config.APIVersion)
-
-	// Add request-specific headers
-	for key, value := range req.Headers {
-		httpReq.Header.Set(key, value)
-	}
-
-	// Add query parameters
-	if len(req.Params) > 0 {
-		q := httpReq.URL.Query()
-		for key, value := range req.Params {
-			q.Add(key, value)
-		}
-		httpReq.URL.RawQuery = q.Encode()
-	}
-
-	resp, err := c.httpClient.Do(httpReq)
-	if err != nil {
-		return fmt.Errorf("error making HTTP request: %w", err)
-	}
-	defer resp.Body.Close()
-
-	// Read the response body
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return fmt.Errorf("error reading response body: %w", err)
+		return err
 	}
 
 	// Check for error status codes
@@ -105,13 +154,13 @@ func (c *Client) DoRequest(ctx context.Context, req Request, result interface{})
 			Error   string `json:"error"`
 		}
 
-		if err := json.Unmarshal(body, &errorResp); err != nil {
-			return fmt.Errorf("API request failed with status code %d: %s", resp.StatusCode, string(body))
-		}
-
-		errorMessage := errorResp.Message
-		if errorResp.Error != "" {
-			errorMessage = errorResp.Error
+		// Fall back to the status text when the body is not a JSON error
+		errorMessage := http.StatusText(resp.StatusCode)
+		if err := json.Unmarshal(body, &errorResp); err == nil {
+			errorMessage = errorResp.Message
+			if errorResp.Error != "" {
+				errorMessage = errorResp.Error
+			}
 		}
 
 		return NewIremboPayError(resp.StatusCode, errorMessage, string(body))
@@ -125,7 +174,7 @@ func (c *Client) DoRequest(ctx context.Context, req Request, result interface{})
 		}
 
 		if !apiResp.Success {
-			return fmt.Errorf("API request unsuccessful: %s", apiResp.Message)
+			return NewIremboPayError(resp.StatusCode, apiResp.Message, string(body))
 		}
 
 		// Parse the data field into the provided result
@@ -136,3 +185,112 @@ func (c *Client) DoRequest(ctx context.Context, req Request, result interface{})
 
 	return nil
 }
+
+// send performs the request, retrying it according to the configured retry policy
+func (c *Client) send(ctx context.Context, req Request, body []byte, info *callInfo) (*http.Response, []byte, error) {
+	policy := c.config.Retry
+	maxAttempts := policy.attempts()
+
+	for attempt := 1; ; attempt++ {
+		httpReq, err := c.newHTTPRequest(ctx, req, body)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		release, err := c.acquire(ctx)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		// Check the circuit breaker once the request is ready to be sent, so
+		// that waiting for the limiter neither counts as a failure nor holds
+		// a half-open trial
+		done, err := c.guard(ctx, req)
+		if err != nil {
+			release()
+			return nil, nil, err
+		}
+
+		start := time.Now()
+		resp, respBody, err := c.roundTrip(httpReq)
+		release()
+		done(resp, err)
+		if c.limiter != nil {
+			c.limiter.observe(resp)
+		}
+		c.logAttempt(ctx, req, httpReq, body, attempt, time.Since(start), resp, respBody, err)
+		info.attempts, info.resp, info.body = attempt, resp, respBody
+		if attempt >= maxAttempts || !canReplay(httpReq) || ctx.Err() != nil {
+			return resp, respBody, err
+		}
+
+		// Decide whether the attempt should be retried and how long to wait
+		var delay time.Duration
+		switch {
+		case err != nil:
+			delay = policy.backoff(attempt)
+		case policy.isRetryableStatus(resp.StatusCode):
+			delay = policy.backoff(attempt)
+			if retryAfter, ok := policy.retryAfter(resp); ok {
+				delay = retryAfter
+			}
+		default:
+			return resp, respBody, nil
+		}
+
+		if err := sleepContext(ctx, delay); err != nil {
+			return nil, nil, fmt.Errorf("error waiting to retry request: %w", err)
+		}
+	}
+}
+
+// newHTTPRequest builds the HTTP request for a single attempt
+func (c *Client) newHTTPRequest(ctx context.Context, req Request, body []byte) (*http.Request, error) {
+	var bodyReader io.Reader
+	if body != nil {
+		bodyReader = bytes.NewReader(body)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, req.Method, c.config.endpoint(req.Path), bodyReader)
+	if err != nil {
+		return nil, fmt.Errorf("error creating HTTP request: %w", err)
+	}
+
+	// Set default headers
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Accept", "application/json")
+	httpReq.Header.Set("irembopay-secretKey", c.config.SecretKey)
+	httpReq.Header.Set("X-API-Version", c.config.APIVersion)
+
+	// Add request-specific headers
+	for key, value := range req.Headers {
+		httpReq.Header.Set(key, value)
+	}
+
+	// Add query parameters
+	if len(req.Params) > 0 {
+		q := httpReq.URL.Query()
+		for key, value := range req.Params {
+			q.Add(key, value)
+		}
+		httpReq.URL.RawQuery = q.Encode()
+	}
+
+	return httpReq, nil
+}
+
+// roundTrip sends a single HTTP request and reads the whole response body
+func (c *Client) roundTrip(httpReq *http.Request) (*http.Response, []byte, error) {
+	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, nil, &NetworkError{Op: "making HTTP request", Err: err}
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, nil, &NetworkError{Op: "reading response body", Err: err}
+	}
+
+	return resp, body, nil
+}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/cruso003/irembopay"
+)
+
+func runBatchCreate(args []string, stdin io.Reader, stdout io.Writer) error {
+	var common commonFlags
+	var req irembopay.BatchInvoiceRequest
+
+	fs := newFlagSet("batch create", &common)
+	fs.StringVar(&req.TransactionID, "transaction-id", "", "Unique transaction identifier (required)")
+	fs.StringVar(&req.Description, "description", "", "Description of the batch invoice")
+	fs.StringVar(&req.IdempotencyKey, "idempotency-key", "", "Idempotency key")
+	if err := parseFlags(fs, &common, args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		return fmt.Errorf("%w: expected the invoice numbers to include in the batch", errUsage)
+	}
+	req.InvoiceNumbers = fs.Args()
+
+	client, err := common.newClient()
+	if err != nil {
+		return err
+	}
+
+	invoice, err := client.Batch.Create(context.Background(), &req)
+	if err != nil {
+		return err
+	}
+	return common.print(stdout, invoice)
+}
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/cruso003/irembopay"
+)
+
+// commonFlags holds the flags shared by all subcommands
+type commonFlags struct {
+	env     string
+	output  string
+	baseURL string
+}
+
+// newFlagSet creates the flag set of a subcommand with the common flags
+func newFlagSet(name string, common *commonFlags) *flag.FlagSet {
+	fs := flag.NewFlagSet("irembopay "+name, flag.ContinueOnError)
+	fs.StringVar(&common.env, "env", "sandbox", "Environment: sandbox or production")
+	fs.StringVar(&common.output, "output", "table", "Output format: table or json")
+	fs.StringVar(&common.baseURL, "base-url", "", "Override the API base URL")
+	return fs
+}
+
+// parseFlags parses the arguments of a subcommand
+func parseFlags(fs *flag.FlagSet, common *commonFlags, args []string) error {
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return err
+		}
+		return fmt.Errorf("%w: %v", errUsage, err)
+	}
+	if common.output != "table" && common.output != "json" {
+		return fmt.Errorf("%w: output must be table or json", errUsage)
+	}
+	return nil
+}
+
+// secretKey reads the secret key of the environment from the process environment
+func (c *commonFlags) secretKey() (string, error) {
+	var name string
+	switch irembopay.EnvironmentType(c.env) {
+	case irembopay.Sandbox:
+		name = "IREMBOPAY_SANDBOX_SECRET_KEY"
+	case irembopay.Production:
+		name = "IREMBOPAY_PRODUCTION_SECRET_KEY"
+	default:
+		return "", fmt.Errorf("%w: env must be sandbox or production", errUsage)
+	}
+
+	key := os.Getenv(name)
+	if key == "" {
+		return "", fmt.Errorf("%s is not set", name)
+	}
+	return key, nil
+}
+
+// newClient creates an API client for the selected environment
+func (c *commonFlags) newClient() (*irembopay.IremboPay, error) {
+	secretKey, err := c.secretKey()
+	if err != nil {
+		return nil, err
+	}
+
+	var opts []irembopay.ConfigOption
+	if c.baseURL != "" {
+		baseURL, err := url.Parse(c.baseURL)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid base URL: %v", errUsage, err)
+		}
+		opts = append(opts, irembopay.WithBaseURL(baseURL))
+	}
+
+	config, err := irembopay.NewConfig(irembopay.EnvironmentType(c.env), secretKey, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return irembopay.NewIremboPay(config), nil
+}
+
+// print writes a result in the selected output format
+func (c *commonFlags) print(w io.Writer, v interface{}) error {
+	if c.output == "json" {
+		return printJSON(w, v)
+	}
+	return printTable(w, v)
+}
+
+// itemsFlag collects payment items given as code:quantity:unitAmount
+type itemsFlag []irembopay.PaymentItem
+
+// String implements the flag.Value interface
+func (f *itemsFlag) String() string {
+	items := make([]string, len(*f))
+	for i, item := range *f {
+		items[i] = fmt.Sprintf("%s:%d:%s", item.Code, item.Quantity, strconv.FormatFloat(item.UnitAmount, 'f', -1, 64))
+	}
+	return strings.Join(items, ",")
+}
+
+// Set implements the flag.Value interface
+func (f *itemsFlag) Set(value string) error {
+	parts := strings.Split(value, ":")
+	if len(parts) != 3 {
+		return fmt.Errorf("payment item must be code:quantity:unitAmount")
+	}
+
+	quantity, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return fmt.Errorf("invalid quantity: %q", parts[1])
+	}
+	unitAmount, err := strconv.ParseFloat(parts[2], 64)
+	if err != nil {
+		return fmt.Errorf("invalid unit amount: %q", parts[2])
+	}
+
+	*f = append(*f, irembopay.PaymentItem{
+		Code:       parts[0],
+		Quantity:   quantity,
+		UnitAmount: unitAmount,
+	})
+	return nil
+}
+
+// stringsFlag collects a repeated string flag
+type stringsFlag []string
+
+// String implements the flag.Value interface
+func (f *stringsFlag) String() string {
+	return strings.Join(*f, ",")
+}
+
+// Set implements the flag.Value interface
+func (f *stringsFlag) Set(value string) error {
+	*f = append(*f, value)
+	return nil
+}
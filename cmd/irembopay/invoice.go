@@ -0,0 +1,258 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/cruso003/irembopay"
+)
+
+func runInvoiceCreate(args []string, stdin io.Reader, stdout io.Writer) error {
+	var common commonFlags
+	var items itemsFlag
+	var req irembopay.InvoiceRequest
+	var customer irembopay.Customer
+	var expiry, expiresIn, language, idempotencyKey string
+
+	fs := newFlagSet("invoice create", &common)
+	fs.StringVar(&req.TransactionID, "transaction-id", "", "Unique transaction identifier (required)")
+	fs.StringVar(&req.PaymentAccountIdentifier, "account", "", "Payment account identifier (required)")
+	fs.Var(&items, "item", "Payment item as code:quantity:unitAmount (repeatable, required)")
+	fs.StringVar(&expiry, "expiry", "", "Expiry time (RFC3339)")
+	fs.StringVar(&expiresIn, "expires-in", "", "Expiry as a duration from now, e.g. 24h")
+	fs.StringVar(&req.Description, "description", "", "Description of the invoice")
+	fs.StringVar(&customer.Name, "customer-name", "", "Customer name")
+	fs.StringVar(&customer.Email, "customer-email", "", "Customer email")
+	fs.StringVar(&customer.PhoneNumber, "customer-phone", "", "Customer phone number")
+	fs.StringVar(&language, "language", "", "Language: EN, FR or RW")
+	fs.StringVar(&idempotencyKey, "idempotency-key", "", "Idempotency key")
+	if err := parseFlags(fs, &common, args); err != nil {
+		return err
+	}
+
+	req.PaymentItems = items
+	req.IdempotencyKey = idempotencyKey
+	req.Language = irembopay.Language(strings.ToUpper(language))
+	if customer != (irembopay.Customer{}) {
+		req.Customer = &customer
+	}
+
+	switch {
+	case expiry != "" && expiresIn != "":
+		return fmt.Errorf("%w: --expiry and --expires-in are mutually exclusive", errUsage)
+	case expiry != "":
+		req.ExpiryAt = expiry
+	case expiresIn != "":
+		d, err := time.ParseDuration(expiresIn)
+		if err != nil {
+			return fmt.Errorf("%w: invalid --expires-in: %v", errUsage, err)
+		}
+		req.ExpiryAt = irembopay.FormatTime(time.Now().Add(d))
+	}
+
+	client, err := common.newClient()
+	if err != nil {
+		return err
+	}
+
+	invoice, err := client.Invoice.Create(context.Background(), &req)
+	if err != nil {
+		return err
+	}
+	return common.print(stdout, invoice)
+}
+
+func runInvoiceGet(args []string, stdin io.Reader, stdout io.Writer) error {
+	var common commonFlags
+	fs := newFlagSet("invoice get", &common)
+	if err := parseFlags(fs, &common, args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return fmt.Errorf("%w: expected an invoice number or transaction ID", errUsage)
+	}
+
+	client, err := common.newClient()
+	if err != nil {
+		return err
+	}
+
+	invoice, err := client.Invoice.Get(context.Background(), fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	return common.print(stdout, invoice)
+}
+
+func runInvoiceList(args []string, stdin io.Reader, stdout io.Writer) error {
+	var common commonFlags
+	var filter irembopay.InvoiceFilter
+	var status, from, to string
+	var all bool
+
+	fs := newFlagSet("invoice list", &common)
+	fs.StringVar(&status, "status", "", "Payment status: NEW, PAID or CANCELLED")
+	fs.StringVar(&filter.PaymentAccountIdentifier, "account", "", "Payment account identifier")
+	fs.StringVar(&filter.CustomerEmail, "customer-email", "", "Customer email")
+	fs.StringVar(&filter.CustomerPhoneNumber, "customer-phone", "", "Customer phone number")
+	fs.StringVar(&from, "from", "", "Only invoices created at or after this time (RFC3339)")
+	fs.StringVar(&to, "to", "", "Only invoices created before this time (RFC3339)")
+	fs.IntVar(&filter.Page, "page", 1, "Page number")
+	fs.IntVar(&filter.PageSize, "size", irembopay.DefaultPageSize, "Invoices per page")
+	fs.BoolVar(&all, "all", false, "List the invoices of all pages, starting at --page")
+	if err := parseFlags(fs, &common, args); err != nil {
+		return err
+	}
+
+	filter.PaymentStatus = irembopay.PaymentStatus(strings.ToUpper(status))
+	var err error
+	if filter.CreatedFrom, err = parseTimeFlag("from", from); err != nil {
+		return err
+	}
+	if filter.CreatedTo, err = parseTimeFlag("to", to); err != nil {
+		return err
+	}
+
+	client, err := common.newClient()
+	if err != nil {
+		return err
+	}
+
+	var invoices []irembopay.Invoice
+	if all {
+		for invoice, err := range client.Invoice.ListAll(context.Background(), &filter) {
+			if err != nil {
+				return err
+			}
+			invoices = append(invoices, *invoice)
+		}
+	} else {
+		page, err := client.Invoice.List(context.Background(), &filter)
+		if err != nil {
+			return err
+		}
+		if common.output == "json" {
+			return printJSON(stdout, page)
+		}
+		invoices = page.Invoices
+	}
+
+	if common.output == "json" {
+		return printJSON(stdout, invoices)
+	}
+	return printInvoiceRows(stdout, invoices)
+}
+
+func runInvoiceUpdate(args []string, stdin io.Reader, stdout io.Writer) error {
+	var common commonFlags
+	var items itemsFlag
+	var req irembopay.UpdateInvoiceRequest
+
+	fs := newFlagSet("invoice update", &common)
+	fs.StringVar(&req.ExpiryAt, "expiry", "", "New expiry time (RFC3339)")
+	fs.Var(&items, "item", "Payment item as code:quantity:unitAmount (repeatable)")
+	if err := parseFlags(fs, &common, args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return fmt.Errorf("%w: expected an invoice number", errUsage)
+	}
+	req.PaymentItems = items
+
+	client, err := common.newClient()
+	if err != nil {
+		return err
+	}
+
+	invoice, err := client.Invoice.Update(context.Background(), fs.Arg(0), &req)
+	if err != nil {
+		return err
+	}
+	return common.print(stdout, invoice)
+}
+
+func runInvoiceExtend(args []string, stdin io.Reader, stdout io.Writer) error {
+	var common commonFlags
+	var by time.Duration
+
+	fs := newFlagSet("invoice extend", &common)
+	fs.DurationVar(&by, "by", 24*time.Hour, "Duration to add to the current expiry time")
+	if err := parseFlags(fs, &common, args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return fmt.Errorf("%w: expected an invoice number", errUsage)
+	}
+	if by <= 0 {
+		return fmt.Errorf("%w: --by must be positive", errUsage)
+	}
+
+	client, err := common.newClient()
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	invoice, err := client.Invoice.Get(ctx, fs.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	// Extend from the current expiry time, or from now if it already passed
+	base := time.Now()
+	if invoice.ExpiryAt != "" {
+		expiry, err := irembopay.ParseTime(invoice.ExpiryAt)
+		if err != nil {
+			return fmt.Errorf("invalid expiry time of invoice: %w", err)
+		}
+		if expiry.After(base) {
+			base = expiry
+		}
+	}
+
+	invoice, err = client.Invoice.UpdateExpiryTime(ctx, invoice.InvoiceNumber, base.Add(by))
+	if err != nil {
+		return err
+	}
+	return common.print(stdout, invoice)
+}
+
+func runInvoiceCancel(args []string, stdin io.Reader, stdout io.Writer) error {
+	var common commonFlags
+	var reason string
+
+	fs := newFlagSet("invoice cancel", &common)
+	fs.StringVar(&reason, "reason", "", "Reason for the cancellation")
+	if err := parseFlags(fs, &common, args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return fmt.Errorf("%w: expected an invoice number", errUsage)
+	}
+
+	client, err := common.newClient()
+	if err != nil {
+		return err
+	}
+
+	invoice, err := client.Invoice.Cancel(context.Background(), fs.Arg(0), reason)
+	if err != nil {
+		return err
+	}
+	return common.print(stdout, invoice)
+}
+
+// parseTimeFlag parses an optional RFC3339 time flag
+func parseTimeFlag(name, value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	t, err := irembopay.ParseTime(value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: invalid --%s: %v", errUsage, name, err)
+	}
+	return t, nil
+}
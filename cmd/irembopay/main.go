@@ -0,0 +1,160 @@
+// Command irembopay is a command-line tool for the IremboPay API.
+//
+// Usage:
+//
+//	irembopay invoice create --transaction-id TST-1 --account TST-RWF --item PI-3e5fe23f2d:1:2000
+//	irembopay invoice get 880419623157
+//	irembopay invoice list --status PAID --from 2025-01-01T00:00:00Z --all
+//	irembopay invoice update 880419623157 --expiry 2025-01-31T12:00:00Z
+//	irembopay invoice extend 880419623157 --by 24h
+//	irembopay invoice cancel 880419623157 --reason "Order cancelled"
+//	irembopay batch create --transaction-id TST-BATCH-1 880419623157 880419623158
+//	irembopay momo initiate --invoice 880419623157 --phone 0780000001
+//	irembopay webhook verify --signature "t=..., s=..." < payload.json
+//	irembopay webhook sign < payload.json
+//
+// The secret key is read from IREMBOPAY_SANDBOX_SECRET_KEY or
+// IREMBOPAY_PRODUCTION_SECRET_KEY depending on the --env flag.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/cruso003/irembopay"
+)
+
+// Exit codes
+const (
+	exitOK           = 0
+	exitError        = 1
+	exitUsage        = 2
+	exitValidation   = 3
+	exitNotFound     = 4
+	exitBadRequest   = 5
+	exitUnauthorized = 6
+	exitConflict     = 7
+	exitRateLimited  = 8
+	exitServerError  = 9
+	exitNetwork      = 10
+)
+
+// errUsage reports invalid command-line arguments
+var errUsage = errors.New("invalid usage")
+
+// command is a subcommand of the tool
+type command struct {
+	name        string
+	description string
+	run         func(args []string, stdin io.Reader, stdout io.Writer) error
+}
+
+var commands = []struct {
+	group       string
+	subcommands []command
+}{
+	{"invoice", []command{
+		{"create", "Create an invoice", runInvoiceCreate},
+		{"get", "Get an invoice by invoice number or transaction ID", runInvoiceGet},
+		{"list", "List invoices, optionally filtered", runInvoiceList},
+		{"update", "Update the expiry time or payment items of an invoice", runInvoiceUpdate},
+		{"extend", "Extend the expiry time of an invoice", runInvoiceExtend},
+		{"cancel", "Cancel an unpaid invoice", runInvoiceCancel},
+	}},
+	{"batch", []command{
+		{"create", "Create a batch invoice", runBatchCreate},
+	}},
+	{"momo", []command{
+		{"initiate", "Initiate a mobile money payment", runMomoInitiate},
+	}},
+	{"webhook", []command{
+		{"verify", "Verify a webhook signature", runWebhookVerify},
+		{"sign", "Sign a webhook payload", runWebhookSign},
+	}},
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// run executes the command line and returns the exit code
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	if len(args) < 2 {
+		usage(stderr)
+		return exitUsage
+	}
+
+	cmd, ok := findCommand(args[0], args[1])
+	if !ok {
+		usage(stderr)
+		return exitUsage
+	}
+
+	err := cmd.run(args[2:], stdin, stdout)
+	if errors.Is(err, flag.ErrHelp) {
+		return exitOK
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "irembopay: %v\n", err)
+		return exitCode(err)
+	}
+	return exitOK
+}
+
+// findCommand looks up a subcommand by its group and name
+func findCommand(group, name string) (command, bool) {
+	for _, g := range commands {
+		if g.group != group {
+			continue
+		}
+		for _, cmd := range g.subcommands {
+			if cmd.name == name {
+				return cmd, true
+			}
+		}
+	}
+	return command{}, false
+}
+
+// usage prints the list of commands
+func usage(w io.Writer) {
+	fmt.Fprintln(w, "Usage: irembopay <command> <subcommand> [flags] [args]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Commands:")
+	for _, g := range commands {
+		for _, cmd := range g.subcommands {
+			fmt.Fprintf(w, "  %-18s %s\n", g.group+" "+cmd.name, cmd.description)
+		}
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Run 'irembopay <command> <subcommand> -h' for the flags of a subcommand.")
+}
+
+// exitCode maps an error to the exit code of the tool
+func exitCode(err error) int {
+	switch {
+	case errors.Is(err, errUsage):
+		return exitUsage
+	case irembopay.IsValidationError(err):
+		return exitValidation
+	case irembopay.IsNotFoundError(err):
+		return exitNotFound
+	case irembopay.IsConflictError(err), errors.Is(err, irembopay.ErrInvoicePaid):
+		return exitConflict
+	case irembopay.IsBadRequestError(err):
+		return exitBadRequest
+	case irembopay.IsUnauthorizedError(err), irembopay.IsForbiddenError(err):
+		return exitUnauthorized
+	case irembopay.IsRateLimitedError(err):
+		return exitRateLimited
+	case irembopay.IsServerError(err):
+		return exitServerError
+	case irembopay.IsNetworkError(err):
+		return exitNetwork
+	default:
+		return exitError
+	}
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"context"
+	"io"
+	"strings"
+
+	"github.com/cruso003/irembopay"
+)
+
+func runMomoInitiate(args []string, stdin io.Reader, stdout io.Writer) error {
+	var common commonFlags
+	var req irembopay.MomoPaymentRequest
+	var provider string
+
+	fs := newFlagSet("momo initiate", &common)
+	fs.StringVar(&req.InvoiceNumber, "invoice", "", "Invoice number (required)")
+	fs.StringVar(&req.AccountIdentifier, "phone", "", "Phone number to charge (required)")
+	fs.StringVar(&provider, "provider", "", "Payment provider: MTN or AIRTEL (detected from the phone number if empty)")
+	fs.StringVar(&req.TransactionReference, "reference", "", "Optional transaction reference")
+	if err := parseFlags(fs, &common, args); err != nil {
+		return err
+	}
+	req.PaymentProvider = irembopay.PaymentProvider(strings.ToUpper(provider))
+
+	client, err := common.newClient()
+	if err != nil {
+		return err
+	}
+
+	payment, err := client.Payment.InitiateMomoPaymentAutoDetect(context.Background(), &req)
+	if err != nil {
+		return err
+	}
+	return common.print(stdout, payment)
+}
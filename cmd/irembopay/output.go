@@ -0,0 +1,116 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/cruso003/irembopay"
+)
+
+// printJSON writes the value as indented JSON
+func printJSON(w io.Writer, v interface{}) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+// printInvoiceRows writes a table with one row per invoice
+func printInvoiceRows(w io.Writer, invoices []irembopay.Invoice) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "INVOICE NUMBER\tTRANSACTION ID\tAMOUNT\tCURRENCY\tSTATUS\tCREATED AT")
+	for _, invoice := range invoices {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n",
+			invoice.InvoiceNumber, invoice.TransactionID,
+			strconv.FormatFloat(invoice.Amount, 'f', -1, 64), invoice.Currency,
+			invoice.PaymentStatus, invoice.CreatedAt)
+	}
+	return tw.Flush()
+}
+
+// printTable writes the non-empty fields of a struct as a two-column table,
+// using the JSON names of the fields
+func printTable(w io.Writer, v interface{}) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	writeFields(tw, "", reflect.ValueOf(v))
+	return tw.Flush()
+}
+
+// writeFields writes the fields of a struct value, flattening nested structs
+func writeFields(w io.Writer, prefix string, v reflect.Value) {
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return
+		}
+		v = v.Elem()
+	}
+
+	if v.Kind() != reflect.Struct {
+		fmt.Fprintf(w, "%s\t%s\n", strings.TrimSuffix(prefix, "."), formatValue(v))
+		return
+	}
+
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		// Skip empty values, but keep booleans which are meaningful when false
+		value := v.Field(i)
+		if value.IsZero() && value.Kind() != reflect.Bool {
+			continue
+		}
+		if isStruct(value) {
+			writeFields(w, prefix+name+".", value)
+			continue
+		}
+		fmt.Fprintf(w, "%s\t%s\n", prefix+name, formatValue(value))
+	}
+}
+
+// isStruct checks if the value is a struct or a pointer to a struct
+func isStruct(v reflect.Value) bool {
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return false
+		}
+		v = v.Elem()
+	}
+	return v.Kind() == reflect.Struct
+}
+
+// formatValue formats a scalar or a list on a single line
+func formatValue(v reflect.Value) string {
+	switch v.Kind() {
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'f', -1, 64)
+	case reflect.Slice, reflect.Array:
+		items := make([]string, v.Len())
+		for i := range items {
+			items[i] = formatValue(v.Index(i))
+		}
+		return strings.Join(items, ", ")
+	case reflect.Struct, reflect.Pointer:
+		encoded, err := json.Marshal(v.Interface())
+		if err != nil {
+			return fmt.Sprint(v.Interface())
+		}
+		return string(encoded)
+	default:
+		return fmt.Sprint(v.Interface())
+	}
+}
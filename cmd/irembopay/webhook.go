@@ -0,0 +1,147 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/cruso003/irembopay"
+)
+
+// verifyResult is the output of the webhook verify command
+type verifyResult struct {
+	Valid        bool                           `json:"valid"`
+	KeyIndex     int                            `json:"keyIndex"`
+	Fresh        bool                           `json:"fresh"`
+	Notification *irembopay.PaymentNotification `json:"notification,omitempty"`
+}
+
+// signResult is the output of the webhook sign command
+type signResult struct {
+	Signature string `json:"signature"`
+	Payload   string `json:"payload,omitempty"`
+}
+
+func runWebhookVerify(args []string, stdin io.Reader, stdout io.Writer) error {
+	var common commonFlags
+	var signature, payloadFile string
+	var tolerance time.Duration
+
+	fs := newFlagSet("webhook verify", &common)
+	fs.StringVar(&signature, "signature", "", "Value of the irembopay-signature header (required)")
+	fs.StringVar(&payloadFile, "payload-file", "", "File containing the payload (default: stdin)")
+	fs.DurationVar(&tolerance, "tolerance", 0, "Maximum age of the notification (0 skips the check)")
+	if err := parseFlags(fs, &common, args); err != nil {
+		return err
+	}
+	if signature == "" {
+		return fmt.Errorf("%w: --signature is required", errUsage)
+	}
+
+	payload, err := readPayload(payloadFile, stdin)
+	if err != nil {
+		return err
+	}
+
+	client, err := common.newClient()
+	if err != nil {
+		return err
+	}
+
+	keyIndex, valid, err := client.Payment.MatchWebhookSignature(signature, payload)
+	if err != nil {
+		return err
+	}
+
+	result := verifyResult{Valid: valid, KeyIndex: keyIndex, Fresh: true}
+	if tolerance > 0 {
+		result.Fresh, err = client.Payment.ValidateWebhookTimestamp(signature, tolerance)
+		if err != nil {
+			return err
+		}
+	}
+	if valid {
+		result.Notification, err = client.Payment.ParseNotification(payload)
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := common.print(stdout, result); err != nil {
+		return err
+	}
+	if !result.Valid {
+		return errors.New("invalid signature")
+	}
+	if !result.Fresh {
+		return errors.New("timestamp is too old")
+	}
+	return nil
+}
+
+func runWebhookSign(args []string, stdin io.Reader, stdout io.Writer) error {
+	var common commonFlags
+	var payloadFile, at string
+	var sample bool
+
+	fs := newFlagSet("webhook sign", &common)
+	fs.StringVar(&payloadFile, "payload-file", "", "File containing the payload (default: stdin)")
+	fs.StringVar(&at, "time", "", "Signing time (RFC3339, default: now)")
+	fs.BoolVar(&sample, "sample", false, "Sign a sample payment notification instead of a payload")
+	if err := parseFlags(fs, &common, args); err != nil {
+		return err
+	}
+
+	signedAt := time.Now()
+	if at != "" {
+		var err error
+		signedAt, err = irembopay.ParseTime(at)
+		if err != nil {
+			return fmt.Errorf("%w: invalid --time: %v", errUsage, err)
+		}
+	}
+
+	secretKey, err := common.secretKey()
+	if err != nil {
+		return err
+	}
+
+	var result signResult
+	if sample {
+		result.Payload, result.Signature, err = irembopay.NewNotificationBuilder().
+			WithPaidAt(signedAt).
+			Sign(secretKey, signedAt)
+		if err != nil {
+			return err
+		}
+	} else {
+		payload, err := readPayload(payloadFile, stdin)
+		if err != nil {
+			return err
+		}
+		result.Signature = irembopay.SignWebhook(secretKey, payload, signedAt)
+	}
+
+	if common.output == "table" && !sample {
+		_, err := fmt.Fprintln(stdout, result.Signature)
+		return err
+	}
+	return common.print(stdout, result)
+}
+
+// readPayload reads a webhook payload from a file, or from stdin if no file is given
+func readPayload(path string, stdin io.Reader) (string, error) {
+	var data []byte
+	var err error
+	if path != "" {
+		data, err = os.ReadFile(path)
+	} else {
+		data, err = io.ReadAll(stdin)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read payload: %w", err)
+	}
+	return string(data), nil
+}
@@ -2,6 +2,11 @@ package irembopay
 
 import (
 	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
 )
 
 // EnvironmentType represents the IremboPay environment (sandbox or production)
@@ -21,6 +26,40 @@ type Config struct {
 	APIVersion  string          // API version (default: "2")
 	Environment EnvironmentType // Sandbox or Production
 	Host        string          // API host URL
+	BaseURL     *url.URL        // Full base URL, overrides Host when set
+	Retry       RetryPolicy     // Retry policy for failed requests (default: no retries)
+
+	// PreviousSecretKeys are still accepted when verifying webhook signatures
+	// during a secret key rotation
+	PreviousSecretKeys []string
+
+	// Webhook replay protection
+	ReplayStore ReplayStore   // Store of accepted webhook deliveries (default: none)
+	ReplayTTL   time.Duration // Time a delivery is remembered (default: 24h)
+
+	// DisableRequestValidation skips the client-side validation of requests
+	DisableRequestValidation bool
+
+	// HTTP configuration
+	HTTPClient *http.Client  // Base HTTP client (default: http.Client with a 30s timeout)
+	Timeout    time.Duration // Overall timeout of a single HTTP attempt (overrides HTTPClient.Timeout)
+	Middleware []Middleware  // Middleware applied to every request, outermost first
+
+	// Client-side throttling, shared by all the services of a client
+	RateLimit      float64 // Average requests per second (default: unlimited)
+	RateBurst      int     // Requests allowed in a burst above the rate (default: 1)
+	MaxConcurrency int     // Maximum number of requests in flight (default: unlimited)
+
+	// CircuitBreaker fails requests fast while the API is failing (default: disabled)
+	CircuitBreaker *CircuitBreakerPolicy
+
+	// Logging
+	Logger    *slog.Logger     // Logger for requests and webhook deliveries (default: none)
+	Redaction *RedactionPolicy // Values hidden from log records (default: DefaultRedactionPolicy)
+
+	// Instrumentation
+	Tracer Tracer // Tracer for API calls and webhook deliveries (default: no-op)
+	Meter  Meter  // Meter for API call and webhook metrics (default: no-op)
 }
 
 // NewConfig creates a new IremboPay configuration
@@ -72,16 +111,188 @@ func WithHost(host string) ConfigOption {
 	}
 }
 
+// WithBaseURL sets the full base URL of the API, including scheme, port and
+// an optional path prefix (for example http://127.0.0.1:8080/irembopay)
+func WithBaseURL(baseURL *url.URL) ConfigOption {
+	return func(c *Config) {
+		c.BaseURL = baseURL
+		if baseURL != nil {
+			c.Host = baseURL.Host
+		}
+	}
+}
+
+// WithRetryPolicy sets the retry policy for failed requests
+func WithRetryPolicy(policy RetryPolicy) ConfigOption {
+	return func(c *Config) {
+		c.Retry = policy
+	}
+}
+
+// WithPreviousSecretKeys sets the previous secret keys accepted when verifying
+// webhook signatures, so notifications signed before a key rotation remain valid
+func WithPreviousSecretKeys(keys ...string) ConfigOption {
+	return func(c *Config) {
+		c.PreviousSecretKeys = keys
+	}
+}
+
+// WithReplayStore enables webhook replay protection: deliveries are recorded
+// in the store for the given duration and duplicates are rejected
+func WithReplayStore(store ReplayStore, ttl time.Duration) ConfigOption {
+	return func(c *Config) {
+		c.ReplayStore = store
+		c.ReplayTTL = ttl
+	}
+}
+
+// WithoutRequestValidation disables the client-side validation of requests,
+// leaving all checks to the API
+func WithoutRequestValidation() ConfigOption {
+	return func(c *Config) {
+		c.DisableRequestValidation = true
+	}
+}
+
+// WithHTTPClient sets the base HTTP client used to send requests.
+// The client is copied, so its transport is never modified.
+func WithHTTPClient(httpClient *http.Client) ConfigOption {
+	return func(c *Config) {
+		c.HTTPClient = httpClient
+	}
+}
+
+// WithTimeout sets the timeout of a single HTTP attempt
+func WithTimeout(timeout time.Duration) ConfigOption {
+	return func(c *Config) {
+		c.Timeout = timeout
+	}
+}
+
+// WithMiddleware appends middleware wrapping every request sent to the API
+func WithMiddleware(middleware ...Middleware) ConfigOption {
+	return func(c *Config) {
+		c.Middleware = append(c.Middleware, middleware...)
+	}
+}
+
+// WithRateLimit limits the requests sent by the client to rps per second on
+// average, with bursts of up to burst requests. Requests wait for their turn
+// until their context is done. The limiter also pauses all requests when the
+// API reports that its rate limit is exhausted (429 with Retry-After, or
+// X-RateLimit-Remaining: 0 with X-RateLimit-Reset), and resumes at rps once the
+// pause ends rather than with a burst.
+func WithRateLimit(rps float64, burst int) ConfigOption {
+	return func(c *Config) {
+		c.RateLimit = rps
+		c.RateBurst = burst
+	}
+}
+
+// WithMaxConcurrency limits the number of requests in flight at the same time
+func WithMaxConcurrency(n int) ConfigOption {
+	return func(c *Config) {
+		c.MaxConcurrency = n
+	}
+}
+
+// WithCircuitBreaker enables a circuit breaker: once too many requests fail,
+// requests are rejected with ErrCircuitOpen without being sent until the API
+// recovers
+func WithCircuitBreaker(policy CircuitBreakerPolicy) ConfigOption {
+	return func(c *Config) {
+		c.CircuitBreaker = &policy
+	}
+}
+
+// WithLogger logs every API request attempt (method, path, status, latency,
+// idempotency key and attempt number) and webhook delivery to the logger.
+// Sensitive values are redacted according to the redaction policy.
+func WithLogger(logger *slog.Logger) ConfigOption {
+	return func(c *Config) {
+		c.Logger = logger
+	}
+}
+
+// WithRedactionPolicy sets the values hidden from log records, replacing
+// DefaultRedactionPolicy
+func WithRedactionPolicy(policy RedactionPolicy) ConfigOption {
+	return func(c *Config) {
+		c.Redaction = &policy
+	}
+}
+
+// WithTracer traces API calls and webhook deliveries with the tracer
+func WithTracer(tracer Tracer) ConfigOption {
+	return func(c *Config) {
+		c.Tracer = tracer
+	}
+}
+
+// WithMeter records metrics of API calls and webhook deliveries with the meter
+func WithMeter(meter Meter) ConfigOption {
+	return func(c *Config) {
+		c.Meter = meter
+	}
+}
+
 // Validate checks if the configuration is valid
 func (c *Config) Validate() error {
 	if c.SecretKey == "" {
 		return fmt.Errorf("secret key is required")
 	}
+	for _, key := range c.PreviousSecretKeys {
+		if key == "" {
+			return fmt.Errorf("previous secret keys cannot be empty")
+		}
+	}
 	if c.APIVersion == "" {
 		return fmt.Errorf("API version is required")
 	}
-	if c.Host == "" {
+	if c.BaseURL != nil {
+		if c.BaseURL.Scheme != "http" && c.BaseURL.Scheme != "https" {
+			return fmt.Errorf("base URL scheme must be http or https")
+		}
+		if c.BaseURL.Host == "" {
+			return fmt.Errorf("base URL host is required")
+		}
+		if c.BaseURL.RawQuery != "" || c.BaseURL.Fragment != "" {
+			return fmt.Errorf("base URL cannot contain a query or fragment")
+		}
+	} else if c.Host == "" {
 		return fmt.Errorf("host is required")
 	}
+	if c.ReplayTTL < 0 {
+		return fmt.Errorf("replay TTL cannot be negative")
+	}
+	if c.Timeout < 0 {
+		return fmt.Errorf("timeout cannot be negative")
+	}
+	if c.RateLimit < 0 || c.RateBurst < 0 {
+		return fmt.Errorf("rate limit cannot be negative")
+	}
+	if c.MaxConcurrency < 0 {
+		return fmt.Errorf("max concurrency cannot be negative")
+	}
+	if err := c.Retry.Validate(); err != nil {
+		return fmt.Errorf("invalid retry policy: %w", err)
+	}
+	if c.CircuitBreaker != nil {
+		if err := c.CircuitBreaker.Validate(); err != nil {
+			return fmt.Errorf("invalid circuit breaker policy: %w", err)
+		}
+	}
 	return nil
 }
+
+// endpoint returns the full URL of the given API path
+func (c *Config) endpoint(path string) string {
+	if c.BaseURL == nil {
+		return fmt.Sprintf("https://%s%s", c.Host, path)
+	}
+
+	base := *c.BaseURL
+	base.Path = strings.TrimSuffix(base.Path, "/")
+	base.RawPath = ""
+	return base.String() + path
+}
@@ -0,0 +1,247 @@
+package irembopay
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Currency represents an ISO 4217 currency code supported by IremboPay
+type Currency string
+
+const (
+	// RWF is the Rwandan franc
+	RWF Currency = "RWF"
+	// EUR is the euro
+	EUR Currency = "EUR"
+	// GBP is the pound sterling
+	GBP Currency = "GBP"
+	// USD is the United States dollar
+	USD Currency = "USD"
+)
+
+// PaymentStatus represents the payment status of an invoice
+type PaymentStatus string
+
+const (
+	// PaymentStatusNew is the status of an unpaid invoice
+	PaymentStatusNew PaymentStatus = "NEW"
+	// PaymentStatusPaid is the status of a paid invoice
+	PaymentStatusPaid PaymentStatus = "PAID"
+	// PaymentStatusCancelled is the status of an invoice cancelled before being paid
+	PaymentStatusCancelled PaymentStatus = "CANCELLED"
+)
+
+// InvoiceType represents the type of an invoice
+type InvoiceType string
+
+const (
+	// InvoiceTypeSingle is a regular invoice
+	InvoiceTypeSingle InvoiceType = "SINGLE"
+	// InvoiceTypeBatch is an invoice grouping several invoices
+	InvoiceTypeBatch InvoiceType = "BATCH"
+)
+
+// PaymentMethod represents the method used to pay an invoice
+type PaymentMethod string
+
+const (
+	// PaymentMethodMTNMomo is MTN Mobile Money
+	PaymentMethodMTNMomo PaymentMethod = "MTN_MOMO"
+	// PaymentMethodAirtelMoney is Airtel Money
+	PaymentMethodAirtelMoney PaymentMethod = "AIRTEL_MONEY"
+	// PaymentMethodCard is a bank card payment
+	PaymentMethodCard PaymentMethod = "CARD"
+)
+
+// PaymentProvider represents a mobile money provider
+type PaymentProvider string
+
+const (
+	// PaymentProviderMTN is MTN Rwanda
+	PaymentProviderMTN PaymentProvider = "MTN"
+	// PaymentProviderAirtel is Airtel Rwanda
+	PaymentProviderAirtel PaymentProvider = "AIRTEL"
+)
+
+// Language represents the language of an invoice and its notifications
+type Language string
+
+const (
+	// LanguageEN is English
+	LanguageEN Language = "EN"
+	// LanguageFR is French
+	LanguageFR Language = "FR"
+	// LanguageRW is Kinyarwanda
+	LanguageRW Language = "RW"
+)
+
+// IsValid checks if the payment status is known
+func (s PaymentStatus) IsValid() bool {
+	switch s {
+	case PaymentStatusNew, PaymentStatusPaid, PaymentStatusCancelled:
+		return true
+	}
+	return false
+}
+
+// IsValid checks if the invoice type is known
+func (t InvoiceType) IsValid() bool {
+	switch t {
+	case InvoiceTypeSingle, InvoiceTypeBatch:
+		return true
+	}
+	return false
+}
+
+// IsValid checks if the payment method is known
+func (m PaymentMethod) IsValid() bool {
+	switch m {
+	case PaymentMethodMTNMomo, PaymentMethodAirtelMoney, PaymentMethodCard:
+		return true
+	}
+	return false
+}
+
+// IsValid checks if the payment provider is known
+func (p PaymentProvider) IsValid() bool {
+	switch p {
+	case PaymentProviderMTN, PaymentProviderAirtel:
+		return true
+	}
+	return false
+}
+
+// IsValid checks if the currency is supported
+func (c Currency) IsValid() bool {
+	switch c {
+	case RWF, EUR, GBP, USD:
+		return true
+	}
+	return false
+}
+
+// IsValid checks if the language is supported
+func (l Language) IsValid() bool {
+	switch l {
+	case LanguageEN, LanguageFR, LanguageRW:
+		return true
+	}
+	return false
+}
+
+// ParsePaymentStatus parses a payment status, rejecting unknown values
+func ParsePaymentStatus(s string) (PaymentStatus, error) {
+	status := PaymentStatus(normalizeEnum(s))
+	if !status.IsValid() {
+		return "", fmt.Errorf("invalid payment status: %q", s)
+	}
+	return status, nil
+}
+
+// ParseInvoiceType parses an invoice type, rejecting unknown values
+func ParseInvoiceType(s string) (InvoiceType, error) {
+	invoiceType := InvoiceType(normalizeEnum(s))
+	if !invoiceType.IsValid() {
+		return "", fmt.Errorf("invalid invoice type: %q", s)
+	}
+	return invoiceType, nil
+}
+
+// ParsePaymentMethod parses a payment method, rejecting unknown values
+func ParsePaymentMethod(s string) (PaymentMethod, error) {
+	method := PaymentMethod(normalizeEnum(s))
+	if !method.IsValid() {
+		return "", fmt.Errorf("invalid payment method: %q", s)
+	}
+	return method, nil
+}
+
+// ParsePaymentProvider parses a payment provider, rejecting unknown values
+func ParsePaymentProvider(s string) (PaymentProvider, error) {
+	provider := PaymentProvider(normalizeEnum(s))
+	if !provider.IsValid() {
+		return "", fmt.Errorf("invalid payment provider: %q", s)
+	}
+	return provider, nil
+}
+
+// ParseCurrency parses a currency, rejecting unsupported values
+func ParseCurrency(s string) (Currency, error) {
+	currency := Currency(normalizeEnum(s))
+	if !currency.IsValid() {
+		return "", fmt.Errorf("invalid currency: %q", s)
+	}
+	return currency, nil
+}
+
+// ParseLanguage parses a language, rejecting unsupported values
+func ParseLanguage(s string) (Language, error) {
+	language := Language(normalizeEnum(s))
+	if !language.IsValid() {
+		return "", fmt.Errorf("invalid language: %q", s)
+	}
+	return language, nil
+}
+
+// The text unmarshalers normalize the case of the values and preserve unknown
+// values, so that new values introduced by the API do not break decoding.
+// Use IsValid or the Parse functions to reject them.
+
+// MarshalText implements the encoding.TextMarshaler interface
+func (s PaymentStatus) MarshalText() ([]byte, error) { return []byte(s), nil }
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface
+func (s *PaymentStatus) UnmarshalText(text []byte) error {
+	*s = PaymentStatus(normalizeEnum(string(text)))
+	return nil
+}
+
+// MarshalText implements the encoding.TextMarshaler interface
+func (t InvoiceType) MarshalText() ([]byte, error) { return []byte(t), nil }
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface
+func (t *InvoiceType) UnmarshalText(text []byte) error {
+	*t = InvoiceType(normalizeEnum(string(text)))
+	return nil
+}
+
+// MarshalText implements the encoding.TextMarshaler interface
+func (m PaymentMethod) MarshalText() ([]byte, error) { return []byte(m), nil }
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface
+func (m *PaymentMethod) UnmarshalText(text []byte) error {
+	*m = PaymentMethod(normalizeEnum(string(text)))
+	return nil
+}
+
+// MarshalText implements the encoding.TextMarshaler interface
+func (p PaymentProvider) MarshalText() ([]byte, error) { return []byte(p), nil }
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface
+func (p *PaymentProvider) UnmarshalText(text []byte) error {
+	*p = PaymentProvider(normalizeEnum(string(text)))
+	return nil
+}
+
+// MarshalText implements the encoding.TextMarshaler interface
+func (c Currency) MarshalText() ([]byte, error) { return []byte(c), nil }
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface
+func (c *Currency) UnmarshalText(text []byte) error {
+	*c = Currency(normalizeEnum(string(text)))
+	return nil
+}
+
+// MarshalText implements the encoding.TextMarshaler interface
+func (l Language) MarshalText() ([]byte, error) { return []byte(l), nil }
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface
+func (l *Language) UnmarshalText(text []byte) error {
+	*l = Language(normalizeEnum(string(text)))
+	return nil
+}
+
+// normalizeEnum normalizes the case and surrounding spaces of an enum value
+func normalizeEnum(s string) string {
+	return strings.ToUpper(strings.TrimSpace(s))
+}
@@ -0,0 +1,292 @@
+package irembopay
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Environment variables read by ConfigFromEnv and LoadConfig
+const (
+	EnvEnvironment         = "IREMBOPAY_ENVIRONMENT"           // sandbox (default) or production
+	EnvSecretKey           = "IREMBOPAY_SECRET_KEY"            // Secret key for any environment
+	EnvSandboxSecretKey    = "IREMBOPAY_SANDBOX_SECRET_KEY"    // Secret key for the sandbox environment
+	EnvProductionSecretKey = "IREMBOPAY_PRODUCTION_SECRET_KEY" // Secret key for the production environment
+	EnvAPIVersion          = "IREMBOPAY_API_VERSION"           // API version
+	EnvHost                = "IREMBOPAY_HOST"                  // API host
+	EnvBaseURL             = "IREMBOPAY_BASE_URL"              // Full API base URL
+)
+
+// configKeys maps the normalized keys of JSON and YAML config files to environment variables
+var configKeys = map[string]string{
+	"environment":         EnvEnvironment,
+	"secretkey":           EnvSecretKey,
+	"sandboxsecretkey":    EnvSandboxSecretKey,
+	"productionsecretkey": EnvProductionSecretKey,
+	"apiversion":          EnvAPIVersion,
+	"host":                EnvHost,
+	"baseurl":             EnvBaseURL,
+}
+
+// ConfigFromEnv creates a configuration from IREMBOPAY_* environment variables.
+// The secret key is read from IREMBOPAY_SECRET_KEY, falling back to
+// IREMBOPAY_SANDBOX_SECRET_KEY or IREMBOPAY_PRODUCTION_SECRET_KEY depending
+// on IREMBOPAY_ENVIRONMENT. Options are applied last and take precedence.
+func ConfigFromEnv(opts ...ConfigOption) (*Config, error) {
+	return configFromValues([]map[string]string{envValues()}, opts)
+}
+
+// LoadConfig creates a configuration from a file and the environment.
+// The file is a .env file (IREMBOPAY_*=value lines), a .json file, or a
+// YAML-style file (.yaml or .yml) with flat "key: value" lines, using the keys
+// environment, secretKey, sandboxSecretKey, productionSecretKey, apiVersion,
+// host and baseURL.
+//
+// Values are resolved in this order of precedence: options, environment
+// variables, the file, then the defaults of NewConfig. The secret key and the
+// endpoint are resolved per source: any secret key set in the environment
+// overrides the secret keys of the file, and IREMBOPAY_HOST or
+// IREMBOPAY_BASE_URL in the environment overrides both of them in the file.
+func LoadConfig(path string, opts ...ConfigOption) (*Config, error) {
+	values, err := readConfigFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return configFromValues([]map[string]string{envValues(), values}, opts)
+}
+
+// envValues returns the IREMBOPAY_* variables set in the process environment
+func envValues() map[string]string {
+	values := make(map[string]string)
+	for _, key := range configKeys {
+		if value, ok := os.LookupEnv(key); ok && value != "" {
+			values[key] = value
+		}
+	}
+	return values
+}
+
+// configFromValues creates a configuration from sources of IREMBOPAY_* values,
+// in order of precedence
+func configFromValues(sources []map[string]string, opts []ConfigOption) (*Config, error) {
+	environment := Sandbox
+	if value := lookupValue(sources, EnvEnvironment); value != "" {
+		environment = EnvironmentType(strings.ToLower(value))
+		if environment != Sandbox && environment != Production {
+			return nil, fmt.Errorf("%s must be sandbox or production, got %q", EnvEnvironment, value)
+		}
+	}
+
+	envSecretKey := EnvSandboxSecretKey
+	if environment == Production {
+		envSecretKey = EnvProductionSecretKey
+	}
+	secretKey := lookupValue(sources, EnvSecretKey, envSecretKey)
+	if secretKey == "" {
+		return nil, fmt.Errorf("secret key is required: set %s or %s", EnvSecretKey, envSecretKey)
+	}
+
+	var fileOpts []ConfigOption
+	if value := lookupValue(sources, EnvAPIVersion); value != "" {
+		fileOpts = append(fileOpts, WithAPIVersion(value))
+	}
+
+	// The first source setting the host or the base URL sets the endpoint
+	for _, values := range sources {
+		if value := values[EnvBaseURL]; value != "" {
+			baseURL, err := url.Parse(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s: %w", EnvBaseURL, err)
+			}
+			fileOpts = append(fileOpts, WithBaseURL(baseURL))
+			break
+		}
+		if value := values[EnvHost]; value != "" {
+			fileOpts = append(fileOpts, WithHost(value))
+			break
+		}
+	}
+
+	return NewConfig(environment, secretKey, append(fileOpts, opts...)...)
+}
+
+// lookupValue returns the first value set for one of the keys, trying all the
+// keys of a source before the next source
+func lookupValue(sources []map[string]string, keys ...string) string {
+	for _, values := range sources {
+		for _, key := range keys {
+			if value := values[key]; value != "" {
+				return value
+			}
+		}
+	}
+	return ""
+}
+
+// readConfigFile reads the IREMBOPAY_* values of a config file
+func readConfigFile(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	var values map[string]string
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".json":
+		values, err = parseJSONConfig(data)
+	case ".yaml", ".yml":
+		values, err = parseYAMLConfig(data)
+	default:
+		values, err = parseDotEnv(data)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+
+	return values, nil
+}
+
+// parseDotEnv parses KEY=VALUE lines, ignoring comments and variables
+// that are not IREMBOPAY_* settings
+func parseDotEnv(data []byte) (map[string]string, error) {
+	values := make(map[string]string)
+
+	err := scanLines(data, func(lineNumber int, line string) error {
+		line = strings.TrimPrefix(line, "export ")
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			return fmt.Errorf("line %d: expected KEY=VALUE", lineNumber)
+		}
+
+		key = strings.TrimSpace(key)
+		value, err := unquoteConfigValue(value)
+		if err != nil {
+			return fmt.Errorf("line %d: %w", lineNumber, err)
+		}
+
+		if isConfigEnvKey(key) {
+			values[key] = value
+		}
+		return nil
+	})
+
+	return values, err
+}
+
+// parseYAMLConfig parses flat "key: value" lines
+func parseYAMLConfig(data []byte) (map[string]string, error) {
+	values := make(map[string]string)
+
+	err := scanLines(data, func(lineNumber int, line string) error {
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			return fmt.Errorf("line %d: expected key: value", lineNumber)
+		}
+
+		envKey, err := configEnvKey(strings.TrimSpace(key))
+		if err != nil {
+			return fmt.Errorf("line %d: %w", lineNumber, err)
+		}
+		value, err = unquoteConfigValue(value)
+		if err != nil {
+			return fmt.Errorf("line %d: %w", lineNumber, err)
+		}
+
+		values[envKey] = value
+		return nil
+	})
+
+	return values, err
+}
+
+// parseJSONConfig parses a flat JSON object
+func parseJSONConfig(data []byte) (map[string]string, error) {
+	var raw map[string]interface{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+
+	values := make(map[string]string)
+	for key, value := range raw {
+		envKey, err := configEnvKey(key)
+		if err != nil {
+			return nil, err
+		}
+
+		switch v := value.(type) {
+		case string:
+			values[envKey] = v
+		case float64:
+			values[envKey] = strconv.FormatFloat(v, 'f', -1, 64)
+		default:
+			return nil, fmt.Errorf("%s must be a string", key)
+		}
+	}
+
+	return values, nil
+}
+
+// scanLines calls fn for every line that is neither blank nor a comment
+func scanLines(data []byte, fn func(lineNumber int, line string) error) error {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if err := fn(lineNumber, line); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
+
+// unquoteConfigValue removes surrounding quotes, or a trailing comment from an unquoted value
+func unquoteConfigValue(value string) (string, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return "", nil
+	}
+
+	switch quote := value[0]; quote {
+	case '"', '\'':
+		end := strings.IndexByte(value[1:], quote)
+		if end < 0 {
+			return "", fmt.Errorf("unterminated quoted value")
+		}
+		return value[1 : end+1], nil
+	}
+
+	if i := strings.Index(value, " #"); i >= 0 {
+		value = strings.TrimSpace(value[:i])
+	}
+	return value, nil
+}
+
+// configEnvKey maps a JSON or YAML key such as secretKey or secret_key to its environment variable
+func configEnvKey(key string) (string, error) {
+	normalized := strings.ToLower(strings.NewReplacer("_", "", "-", "").Replace(key))
+	if envKey, ok := configKeys[normalized]; ok {
+		return envKey, nil
+	}
+	return "", fmt.Errorf("unknown configuration key: %q", key)
+}
+
+// isConfigEnvKey checks if the variable is one of the IREMBOPAY_* settings
+func isConfigEnvKey(key string) bool {
+	for _, envKey := range configKeys {
+		if key == envKey {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,145 @@
+package irembopay_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/cruso003/irembopay"
+)
+
+// clearConfigEnv unsets the IREMBOPAY_* variables for the duration of the test
+func clearConfigEnv(t *testing.T) {
+	t.Helper()
+	for _, key := range []string{
+		irembopay.EnvEnvironment, irembopay.EnvSecretKey, irembopay.EnvSandboxSecretKey,
+		irembopay.EnvProductionSecretKey, irembopay.EnvAPIVersion, irembopay.EnvHost, irembopay.EnvBaseURL,
+	} {
+		t.Setenv(key, "")
+	}
+}
+
+// writeConfigFile writes a config file in a temporary directory
+func writeConfigFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadConfigPrecedence(t *testing.T) {
+	tests := []struct {
+		name       string
+		file       string
+		env        map[string]string
+		wantKey    string
+		wantHost   string
+		wantBase   string
+		wantAPIVer string
+	}{
+		{
+			name:       "file only",
+			file:       "IREMBOPAY_SECRET_KEY=file-key\nIREMBOPAY_HOST=file.example.com\nIREMBOPAY_API_VERSION=3\n",
+			wantKey:    "file-key",
+			wantHost:   "file.example.com",
+			wantAPIVer: "3",
+		},
+		{
+			name:       "environment overrides the same keys",
+			file:       "IREMBOPAY_SECRET_KEY=file-key\nIREMBOPAY_API_VERSION=3\n",
+			env:        map[string]string{irembopay.EnvSecretKey: "env-key", irembopay.EnvAPIVersion: "4"},
+			wantKey:    "env-key",
+			wantHost:   "api.sandbox.irembopay.com",
+			wantAPIVer: "4",
+		},
+		{
+			name:       "environment-specific key in the environment overrides the generic key of the file",
+			file:       "IREMBOPAY_ENVIRONMENT=production\nIREMBOPAY_SECRET_KEY=file-key\n",
+			env:        map[string]string{irembopay.EnvProductionSecretKey: "env-production-key"},
+			wantKey:    "env-production-key",
+			wantHost:   "api.irembopay.com",
+			wantAPIVer: "2",
+		},
+		{
+			name:       "generic key wins within the same source",
+			file:       "IREMBOPAY_SECRET_KEY=file-key\nIREMBOPAY_SANDBOX_SECRET_KEY=file-sandbox-key\n",
+			wantKey:    "file-key",
+			wantHost:   "api.sandbox.irembopay.com",
+			wantAPIVer: "2",
+		},
+		{
+			name:       "host in the environment overrides the base URL of the file",
+			file:       "IREMBOPAY_SECRET_KEY=file-key\nIREMBOPAY_BASE_URL=http://127.0.0.1:8080/irembopay\n",
+			env:        map[string]string{irembopay.EnvHost: "env.example.com"},
+			wantKey:    "file-key",
+			wantHost:   "env.example.com",
+			wantAPIVer: "2",
+		},
+		{
+			name:       "base URL in the environment overrides the host of the file",
+			file:       "IREMBOPAY_SECRET_KEY=file-key\nIREMBOPAY_HOST=file.example.com\n",
+			env:        map[string]string{irembopay.EnvBaseURL: "http://127.0.0.1:8080/irembopay"},
+			wantKey:    "file-key",
+			wantHost:   "127.0.0.1:8080",
+			wantBase:   "http://127.0.0.1:8080/irembopay",
+			wantAPIVer: "2",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearConfigEnv(t)
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+
+			config, err := irembopay.LoadConfig(writeConfigFile(t, ".env", tt.file))
+			if err != nil {
+				t.Fatalf("LoadConfig returned error: %v", err)
+			}
+
+			if config.SecretKey != tt.wantKey {
+				t.Errorf("SecretKey = %q, want %q", config.SecretKey, tt.wantKey)
+			}
+			if config.Host != tt.wantHost {
+				t.Errorf("Host = %q, want %q", config.Host, tt.wantHost)
+			}
+			var base string
+			if config.BaseURL != nil {
+				base = config.BaseURL.String()
+			}
+			if base != tt.wantBase {
+				t.Errorf("BaseURL = %q, want %q", base, tt.wantBase)
+			}
+			if config.APIVersion != tt.wantAPIVer {
+				t.Errorf("APIVersion = %q, want %q", config.APIVersion, tt.wantAPIVer)
+			}
+		})
+	}
+}
+
+func TestLoadConfigOptionsOverrideEnvironment(t *testing.T) {
+	clearConfigEnv(t)
+	t.Setenv(irembopay.EnvAPIVersion, "4")
+
+	path := writeConfigFile(t, "config.json", `{"secretKey": "file-key", "apiVersion": "3"}`)
+	config, err := irembopay.LoadConfig(path, irembopay.WithAPIVersion("5"))
+	if err != nil {
+		t.Fatalf("LoadConfig returned error: %v", err)
+	}
+	if config.APIVersion != "5" {
+		t.Errorf("APIVersion = %q, want 5", config.APIVersion)
+	}
+}
+
+func TestConfigFromEnvRequiresSecretKey(t *testing.T) {
+	clearConfigEnv(t)
+	t.Setenv(irembopay.EnvEnvironment, "production")
+	t.Setenv(irembopay.EnvSandboxSecretKey, "sandbox-key")
+
+	if _, err := irembopay.ConfigFromEnv(); err == nil {
+		t.Error("ConfigFromEnv should fail without a production secret key")
+	}
+}
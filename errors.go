@@ -1,7 +1,46 @@
 package irembopay
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// Sentinel errors used to classify errors returned by the SDK with errors.Is
+var (
+	ErrBadRequest   = errors.New("irembopay: bad request")
+	ErrUnauthorized = errors.New("irembopay: unauthorized")
+	ErrForbidden    = errors.New("irembopay: forbidden")
+	ErrNotFound     = errors.New("irembopay: not found")
+	ErrConflict     = errors.New("irembopay: conflict")
+	ErrRateLimited  = errors.New("irembopay: rate limited")
+	ErrServerError  = errors.New("irembopay: server error")
+	ErrUnsuccessful = errors.New("irembopay: request unsuccessful")
+	ErrNetwork      = errors.New("irembopay: network error")
+	ErrTimeout      = errors.New("irembopay: timeout")
+	ErrValidation   = errors.New("irembopay: invalid request")
+
+	// ErrInvoiceExpired is returned when an invoice expired before being paid
+	ErrInvoiceExpired = errors.New("irembopay: invoice expired")
+
+	// ErrInvoicePaid is returned when an operation requires an unpaid invoice
+	ErrInvoicePaid = errors.New("irembopay: invoice already paid")
+
+	// ErrInvoiceCancelled is returned when an invoice was cancelled before being paid
+	ErrInvoiceCancelled = errors.New("irembopay: invoice cancelled")
+
+	// ErrCircuitOpen is returned when a request is rejected by an open circuit breaker
+	ErrCircuitOpen = errors.New("irembopay: circuit breaker open")
+
+	// ErrDuplicateNotification is returned when a webhook delivery was already accepted
+	ErrDuplicateNotification = errors.New("irembopay: duplicate webhook notification")
+
+	// ErrNotificationInProgress is reported when a webhook delivery is retried
+	// while the previous attempt is still being processed
+	ErrNotificationInProgress = errors.New("irembopay: webhook notification is being processed")
 )
 
 // IremboPayError represents an error from the IremboPay API
@@ -13,9 +52,38 @@ type IremboPayError struct {
 
 // Error implements the error interface
 func (e *IremboPayError) Error() string {
+	if e.StatusCode >= 200 && e.StatusCode < 300 {
+		return fmt.Sprintf("IremboPay API request unsuccessful (HTTP %d): %s", e.StatusCode, e.Message)
+	}
 	return fmt.Sprintf("IremboPay API error (HTTP %d): %s", e.StatusCode, e.Message)
 }
 
+// Is reports whether the error matches one of the sentinel errors
+func (e *IremboPayError) Is(target error) bool {
+	switch target {
+	case ErrBadRequest:
+		return e.StatusCode == http.StatusBadRequest
+	case ErrUnauthorized:
+		return e.StatusCode == http.StatusUnauthorized
+	case ErrForbidden:
+		return e.StatusCode == http.StatusForbidden
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	case ErrConflict:
+		// Duplicate transaction IDs may be reported as bad requests, but not
+		// server errors such as a "duplicate key" database failure
+		return e.StatusCode == http.StatusConflict ||
+			(e.StatusCode == http.StatusBadRequest && strings.Contains(strings.ToLower(e.Message), "duplicate"))
+	case ErrRateLimited:
+		return e.StatusCode == http.StatusTooManyRequests
+	case ErrServerError:
+		return e.StatusCode >= 500
+	case ErrUnsuccessful:
+		return e.StatusCode >= 200 && e.StatusCode < 300
+	}
+	return false
+}
+
 // NewIremboPayError creates a new IremboPayError
 func NewIremboPayError(statusCode int, message, details string) *IremboPayError {
 	return &IremboPayError{
@@ -25,18 +93,112 @@ func NewIremboPayError(statusCode int, message, details string) *IremboPayError
 	}
 }
 
-// IsNotFoundError checks if the error is a 404 Not Found error
-func IsNotFoundError(err error) bool {
-	if iremboErr, ok := err.(*IremboPayError); ok {
-		return iremboErr.StatusCode == 404
+// NetworkError represents a failure to reach the IremboPay API or read its response
+type NetworkError struct {
+	Op  string // Operation that failed
+	Err error  // Underlying error
+}
+
+// Error implements the error interface
+func (e *NetworkError) Error() string {
+	return fmt.Sprintf("error %s: %v", e.Op, e.Err)
+}
+
+// Unwrap returns the underlying error
+func (e *NetworkError) Unwrap() error {
+	return e.Err
+}
+
+// Timeout reports whether the error was caused by a timeout
+func (e *NetworkError) Timeout() bool {
+	if errors.Is(e.Err, context.DeadlineExceeded) {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(e.Err, &netErr) && netErr.Timeout()
+}
+
+// Is reports whether the error matches one of the sentinel errors
+func (e *NetworkError) Is(target error) bool {
+	switch target {
+	case ErrNetwork:
+		return true
+	case ErrTimeout:
+		return e.Timeout()
 	}
 	return false
 }
 
+// AsIremboPayError returns the IremboPayError wrapped in err, if any
+func AsIremboPayError(err error) (*IremboPayError, bool) {
+	var iremboErr *IremboPayError
+	if errors.As(err, &iremboErr) {
+		return iremboErr, true
+	}
+	return nil, false
+}
+
+// IsNotFoundError checks if the error is a 404 Not Found error
+func IsNotFoundError(err error) bool {
+	return errors.Is(err, ErrNotFound)
+}
+
 // IsBadRequestError checks if the error is a 400 Bad Request error
 func IsBadRequestError(err error) bool {
-	if iremboErr, ok := err.(*IremboPayError); ok {
-		return iremboErr.StatusCode == 400
-	}
-	return false
+	return errors.Is(err, ErrBadRequest)
+}
+
+// IsUnauthorizedError checks if the error is a 401 Unauthorized error
+func IsUnauthorizedError(err error) bool {
+	return errors.Is(err, ErrUnauthorized)
+}
+
+// IsForbiddenError checks if the error is a 403 Forbidden error
+func IsForbiddenError(err error) bool {
+	return errors.Is(err, ErrForbidden)
+}
+
+// IsConflictError checks if the error is a conflict, such as a duplicate transaction ID
+func IsConflictError(err error) bool {
+	return errors.Is(err, ErrConflict)
+}
+
+// IsRateLimitedError checks if the error is a 429 Too Many Requests error
+func IsRateLimitedError(err error) bool {
+	return errors.Is(err, ErrRateLimited)
+}
+
+// IsServerError checks if the error is a 5xx server error
+func IsServerError(err error) bool {
+	return errors.Is(err, ErrServerError)
+}
+
+// IsUnsuccessfulError checks if the API answered with success set to false
+func IsUnsuccessfulError(err error) bool {
+	return errors.Is(err, ErrUnsuccessful)
+}
+
+// IsNetworkError checks if the error is a network error
+func IsNetworkError(err error) bool {
+	return errors.Is(err, ErrNetwork)
+}
+
+// IsTimeoutError checks if the error is caused by a timeout
+func IsTimeoutError(err error) bool {
+	return errors.Is(err, ErrTimeout)
+}
+
+// IsValidationError checks if the error is a client-side request validation error
+func IsValidationError(err error) bool {
+	return errors.Is(err, ErrValidation)
+}
+
+// IsDuplicateNotificationError checks if the error reports a replayed webhook delivery
+func IsDuplicateNotificationError(err error) bool {
+	return errors.Is(err, ErrDuplicateNotification)
+}
+
+// IsCircuitOpenError checks if the request was rejected by an open circuit breaker
+func IsCircuitOpenError(err error) bool {
+	return errors.Is(err, ErrCircuitOpen)
 }
@@ -0,0 +1,110 @@
+package irembopay_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/cruso003/irembopay"
+)
+
+// sentinels are the sentinel errors matched by API errors
+var sentinels = []error{
+	irembopay.ErrBadRequest,
+	irembopay.ErrUnauthorized,
+	irembopay.ErrForbidden,
+	irembopay.ErrNotFound,
+	irembopay.ErrConflict,
+	irembopay.ErrRateLimited,
+	irembopay.ErrServerError,
+	irembopay.ErrUnsuccessful,
+}
+
+func TestIremboPayErrorIs(t *testing.T) {
+	tests := []struct {
+		status  int
+		message string
+		want    []error
+		class   string
+	}{
+		{status: http.StatusBadRequest, message: "Invalid amount", want: []error{irembopay.ErrBadRequest}, class: "bad_request"},
+		{status: http.StatusBadRequest, message: "Duplicate transaction ID", want: []error{irembopay.ErrBadRequest, irembopay.ErrConflict}, class: "bad_request"},
+		{status: http.StatusUnauthorized, want: []error{irembopay.ErrUnauthorized}, class: "unauthorized"},
+		{status: http.StatusForbidden, want: []error{irembopay.ErrForbidden}, class: "forbidden"},
+		{status: http.StatusNotFound, want: []error{irembopay.ErrNotFound}, class: "not_found"},
+		{status: http.StatusConflict, message: "Transaction already exists", want: []error{irembopay.ErrConflict}, class: "conflict"},
+		{status: http.StatusTooManyRequests, want: []error{irembopay.ErrRateLimited}, class: "rate_limited"},
+		{status: http.StatusInternalServerError, want: []error{irembopay.ErrServerError}, class: "server_error"},
+		{status: http.StatusInternalServerError, message: "duplicate key value violates unique constraint", want: []error{irembopay.ErrServerError}, class: "server_error"},
+		{status: http.StatusServiceUnavailable, want: []error{irembopay.ErrServerError}, class: "server_error"},
+		{status: http.StatusOK, message: "Duplicate request", want: []error{irembopay.ErrUnsuccessful}, class: "unsuccessful"},
+	}
+
+	for _, tt := range tests {
+		apiErr := irembopay.NewIremboPayError(tt.status, tt.message, "")
+
+		// Errors match through any number of wrapping layers
+		wrapped := fmt.Errorf("failed to create invoice: %w", apiErr)
+		for _, err := range []error{apiErr, wrapped, fmt.Errorf("checkout: %w", wrapped)} {
+			for _, sentinel := range sentinels {
+				want := false
+				for _, w := range tt.want {
+					want = want || w == sentinel
+				}
+				if got := errors.Is(err, sentinel); got != want {
+					t.Errorf("errors.Is(HTTP %d %q, %v) = %v, want %v", tt.status, tt.message, sentinel, got, want)
+				}
+			}
+			if got := irembopay.ErrorClass(err); got != tt.class {
+				t.Errorf("ErrorClass(HTTP %d %q) = %q, want %q", tt.status, tt.message, got, tt.class)
+			}
+		}
+
+		if got, ok := irembopay.AsIremboPayError(wrapped); !ok || got != apiErr {
+			t.Errorf("AsIremboPayError(HTTP %d) = %v, %v, want the wrapped error", tt.status, got, ok)
+		}
+	}
+}
+
+func TestIsConflictError(t *testing.T) {
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{err: irembopay.NewIremboPayError(http.StatusConflict, "", ""), want: true},
+		{err: fmt.Errorf("create: %w", irembopay.NewIremboPayError(http.StatusBadRequest, "DUPLICATE transactionId", "")), want: true},
+		{err: irembopay.NewIremboPayError(http.StatusInternalServerError, "duplicate key", ""), want: false},
+		{err: irembopay.NewIremboPayError(http.StatusBadGateway, "Duplicate", ""), want: false},
+		{err: errors.New("duplicate"), want: false},
+		{err: nil, want: false},
+	}
+
+	for _, tt := range tests {
+		if got := irembopay.IsConflictError(tt.err); got != tt.want {
+			t.Errorf("IsConflictError(%v) = %v, want %v", tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestNetworkErrorIs(t *testing.T) {
+	timeout := fmt.Errorf("get invoice: %w", &irembopay.NetworkError{Op: "sending request", Err: context.DeadlineExceeded})
+	if !irembopay.IsNetworkError(timeout) || !irembopay.IsTimeoutError(timeout) {
+		t.Errorf("a wrapped network timeout is not matched as a network timeout: %v", timeout)
+	}
+	if got := irembopay.ErrorClass(timeout); got != "timeout" {
+		t.Errorf("ErrorClass = %q, want timeout", got)
+	}
+
+	refused := fmt.Errorf("get invoice: %w", &irembopay.NetworkError{Op: "sending request", Err: errors.New("connection refused")})
+	if !irembopay.IsNetworkError(refused) || irembopay.IsTimeoutError(refused) {
+		t.Errorf("a wrapped connection failure is not matched as a network error only: %v", refused)
+	}
+	if got := irembopay.ErrorClass(refused); got != "network" {
+		t.Errorf("ErrorClass = %q, want network", got)
+	}
+	if irembopay.IsServerError(refused) {
+		t.Error("a network error matched ErrServerError")
+	}
+}
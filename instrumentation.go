@@ -0,0 +1,162 @@
+package irembopay
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// Names of the metrics recorded by the SDK
+const (
+	MetricRequests        = "irembopay.client.requests"         // Counter of API calls
+	MetricRequestDuration = "irembopay.client.request.duration" // Histogram of API call latencies, in seconds
+	MetricWebhooks        = "irembopay.webhook.deliveries"      // Counter of webhook deliveries
+	MetricWebhookDuration = "irembopay.webhook.duration"        // Histogram of webhook handling latencies, in seconds
+)
+
+// Keys of the attributes set on spans and metrics
+const (
+	AttrOperation      = "irembopay.operation"       // SDK operation, e.g. invoice.create
+	AttrMethod         = "http.request.method"       // HTTP method
+	AttrStatusCode     = "http.response.status_code" // HTTP status code
+	AttrErrorType      = "error.type"                // Error class, see ErrorClass
+	AttrAttempts       = "irembopay.attempts"        // Number of attempts made
+	AttrInvoiceNumber  = "irembopay.invoice_number"  // Invoice number of a webhook notification
+	AttrWebhookOutcome = "irembopay.webhook.outcome" // accepted, duplicate or rejected
+)
+
+// Attribute is a key-value pair attached to spans and metrics.
+// Values are strings, ints, int64s, float64s or bools.
+type Attribute struct {
+	Key   string
+	Value interface{}
+}
+
+// Tracer starts spans around SDK operations. Implementations adapt a tracing
+// library such as OpenTelemetry; see the otelirembopay module.
+type Tracer interface {
+	// Start starts a span and returns a context carrying it
+	Start(ctx context.Context, name string, attrs ...Attribute) (context.Context, Span)
+}
+
+// Span is an operation traced by a Tracer
+type Span interface {
+	SetAttributes(attrs ...Attribute)
+	RecordError(err error)
+	End()
+}
+
+// Meter creates the instruments recording SDK metrics
+type Meter interface {
+	Counter(name, description string) Counter
+	Histogram(name, description, unit string) Histogram
+}
+
+// Counter is a monotonic metric instrument
+type Counter interface {
+	Add(ctx context.Context, value int64, attrs ...Attribute)
+}
+
+// Histogram is a metric instrument recording a distribution of values
+type Histogram interface {
+	Record(ctx context.Context, value float64, attrs ...Attribute)
+}
+
+// noopTracer is the default tracer, which records nothing
+type noopTracer struct{}
+
+func (noopTracer) Start(ctx context.Context, name string, attrs ...Attribute) (context.Context, Span) {
+	return ctx, noopSpan{}
+}
+
+// noopSpan is a span that records nothing
+type noopSpan struct{}
+
+func (noopSpan) SetAttributes(attrs ...Attribute) {}
+func (noopSpan) RecordError(err error)            {}
+func (noopSpan) End()                             {}
+
+// noopMeter is the default meter, which records nothing
+type noopMeter struct{}
+
+func (noopMeter) Counter(name, description string) Counter           { return noopInstrument{} }
+func (noopMeter) Histogram(name, description, unit string) Histogram { return noopInstrument{} }
+
+// noopInstrument is a counter and histogram that records nothing
+type noopInstrument struct{}
+
+func (noopInstrument) Add(ctx context.Context, value int64, attrs ...Attribute)      {}
+func (noopInstrument) Record(ctx context.Context, value float64, attrs ...Attribute) {}
+
+// tracer returns the configured tracer, or a no-op tracer
+func (c *Config) tracer() Tracer {
+	if c.Tracer != nil {
+		return c.Tracer
+	}
+	return noopTracer{}
+}
+
+// meter returns the configured meter, or a no-op meter
+func (c *Config) meter() Meter {
+	if c.Meter != nil {
+		return c.Meter
+	}
+	return noopMeter{}
+}
+
+// requestMetrics holds the instruments recording API calls
+type requestMetrics struct {
+	requests Counter
+	duration Histogram
+}
+
+// newRequestMetrics creates the instruments recording API calls
+func newRequestMetrics(meter Meter) requestMetrics {
+	return requestMetrics{
+		requests: meter.Counter(MetricRequests, "Number of IremboPay API calls"),
+		duration: meter.Histogram(MetricRequestDuration, "Duration of IremboPay API calls, including retries", "s"),
+	}
+}
+
+// record records a completed API call
+func (m requestMetrics) record(ctx context.Context, latency time.Duration, attrs []Attribute) {
+	m.requests.Add(ctx, 1, attrs...)
+	m.duration.Record(ctx, latency.Seconds(), attrs...)
+}
+
+// ErrorClass returns a short, low-cardinality class for an error returned by
+// the SDK, such as not_found or rate_limited. It returns an empty string for nil.
+func ErrorClass(err error) string {
+	switch {
+	case err == nil:
+		return ""
+	case errors.Is(err, context.Canceled):
+		return "canceled"
+	case IsTimeoutError(err), errors.Is(err, context.DeadlineExceeded):
+		return "timeout"
+	case IsCircuitOpenError(err):
+		return "circuit_open"
+	case IsNetworkError(err):
+		return "network"
+	case IsValidationError(err):
+		return "validation"
+	case IsBadRequestError(err):
+		return "bad_request"
+	case IsUnauthorizedError(err):
+		return "unauthorized"
+	case IsForbiddenError(err):
+		return "forbidden"
+	case IsNotFoundError(err):
+		return "not_found"
+	case IsConflictError(err):
+		return "conflict"
+	case IsRateLimitedError(err):
+		return "rate_limited"
+	case IsServerError(err):
+		return "server_error"
+	case IsUnsuccessfulError(err):
+		return "unsuccessful"
+	default:
+		return "other"
+	}
+}
@@ -23,11 +23,16 @@ func NewInvoiceService(client *Client, config *Config) *InvoiceService {
 
 // Create creates a new invoice
 func (s *InvoiceService) Create(ctx context.Context, req *InvoiceRequest) (*Invoice, error) {
+	if err := validateRequest(s.config, req); err != nil {
+		return nil, fmt.Errorf("failed to create invoice: %w", err)
+	}
+
 	var invoice Invoice
 	apiReq := Request{
-		Method: http.MethodPost,
-		Path:   "/payments/invoices",
-		Body:   req,
+		Operation: "invoice.create",
+		Method:    http.MethodPost,
+		Path:      "/payments/invoices",
+		Body:      req,
 	}
 
 	err := s.client.DoRequest(ctx, apiReq, &invoice)
@@ -57,8 +62,9 @@ func (s *InvoiceService) CreateWithExpiry(ctx context.Context, req *InvoiceReque
 func (s *InvoiceService) Get(ctx context.Context, invoiceReference string) (*Invoice, error) {
 	var invoice Invoice
 	apiReq := Request{
-		Method: http.MethodGet,
-		Path:   fmt.Sprintf("/payments/invoices/%s", invoiceReference),
+		Operation: "invoice.get",
+		Method:    http.MethodGet,
+		Path:      fmt.Sprintf("/payments/invoices/%s", invoiceReference),
 	}
 
 	err := s.client.DoRequest(ctx, apiReq, &invoice)
@@ -71,11 +77,16 @@ func (s *InvoiceService) Get(ctx context.Context, invoiceReference string) (*Inv
 
 // Update updates an existing invoice
 func (s *InvoiceService) Update(ctx context.Context, invoiceNumber string, req *UpdateInvoiceRequest) (*Invoice, error) {
+	if err := validateRequest(s.config, req); err != nil {
+		return nil, fmt.Errorf("failed to update invoice: %w", err)
+	}
+
 	var invoice Invoice
 	apiReq := Request{
-		Method: http.MethodPut,
-		Path:   fmt.Sprintf("/payments/invoices/%s", invoiceNumber),
-		Body:   req,
+		Operation: "invoice.update",
+		Method:    http.MethodPut,
+		Path:      fmt.Sprintf("/payments/invoices/%s", invoiceNumber),
+		Body:      req,
 	}
 
 	err := s.client.DoRequest(ctx, apiReq, &invoice)
@@ -94,3 +105,40 @@ func (s *InvoiceService) UpdateExpiryTime(ctx context.Context, invoiceNumber str
 
 	return s.Update(ctx, invoiceNumber, req)
 }
+
+// Cancel cancels an unpaid invoice, so that it can no longer be paid.
+// It refuses to cancel a paid invoice with ErrInvoicePaid, and returns a
+// cancelled invoice unchanged.
+func (s *InvoiceService) Cancel(ctx context.Context, invoiceNumber, reason string) (*Invoice, error) {
+	if invoiceNumber == "" {
+		v := &ValidationError{}
+		v.add("invoiceNumber", "is required")
+		return nil, fmt.Errorf("failed to cancel invoice: %w", v)
+	}
+
+	invoice, err := s.Get(ctx, invoiceNumber)
+	if err != nil {
+		return nil, fmt.Errorf("failed to cancel invoice: %w", err)
+	}
+	if invoice.IsPaid() {
+		return nil, fmt.Errorf("failed to cancel invoice %s: %w", invoiceNumber, ErrInvoicePaid)
+	}
+	if invoice.IsCancelled() {
+		return invoice, nil
+	}
+
+	var cancelled Invoice
+	apiReq := Request{
+		Operation: "invoice.cancel",
+		Method:    http.MethodPost,
+		Path:      fmt.Sprintf("/payments/invoices/%s/cancel", invoiceNumber),
+		Body:      &CancelInvoiceRequest{Reason: reason},
+	}
+
+	err = s.client.DoRequest(ctx, apiReq, &cancelled)
+	if err != nil {
+		return nil, fmt.Errorf("failed to cancel invoice: %w", err)
+	}
+
+	return &cancelled, nil
+}
@@ -2,22 +2,39 @@ package irembopay
 
 // IremboPay is the main client for interacting with the IremboPay API
 type IremboPay struct {
-	Config  *Config
-	Invoice *InvoiceService
-	Batch   *BatchService
-	Payment *PaymentService
+	Config   *Config
+	Invoice  InvoiceAPI
+	Batch    BatchAPI
+	Payment  PaymentAPI
+	Checkout CheckoutAPI
+
+	client *Client
 }
 
 // NewIremboPay creates a new IremboPay client
 func NewIremboPay(config *Config) *IremboPay {
 	client := NewClient(config)
+	invoice := NewInvoiceService(client, config)
+	payment := NewPaymentService(client, config)
 
 	return &IremboPay{
-		Config:  config,
-		Invoice: NewInvoiceService(client, config),
-		Batch:   NewBatchService(client, config),
-		Payment: NewPaymentService(client, config),
+		Config:   config,
+		Invoice:  invoice,
+		Batch:    NewBatchService(client, config),
+		Payment:  payment,
+		Checkout: NewCheckoutService(invoice, payment),
+		client:   client,
+	}
+}
+
+// CircuitState returns the state of the circuit breaker for an operation,
+// such as invoice.create. The operation is ignored when the circuit is shared
+// by the whole client. It returns CircuitClosed without a circuit breaker.
+func (p *IremboPay) CircuitState(operation string) CircuitState {
+	if p.client == nil {
+		return CircuitClosed
 	}
+	return p.client.CircuitState(operation)
 }
 
 // NewSandboxClient creates a new IremboPay client for the sandbox environment
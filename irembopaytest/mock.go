@@ -0,0 +1,702 @@
+package irembopaytest
+
+// Mock implementations of the irembopay service interfaces.
+//
+// Each method calls the corresponding function field, which must be set by
+// the test, and records its arguments so they can be inspected afterwards.
+
+import (
+	"context"
+	"iter"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/cruso003/irembopay"
+)
+
+// InvoiceAPIMock is a mock implementation of irembopay.InvoiceAPI
+type InvoiceAPIMock struct {
+	// CreateFunc mocks the Create method
+	CreateFunc func(ctx context.Context, req *irembopay.InvoiceRequest) (*irembopay.Invoice, error)
+
+	// CreateWithIdempotencyFunc mocks the CreateWithIdempotency method
+	CreateWithIdempotencyFunc func(ctx context.Context, req *irembopay.InvoiceRequest, idempotencyKey string) (*irembopay.Invoice, error)
+
+	// CreateWithExpiryFunc mocks the CreateWithExpiry method
+	CreateWithExpiryFunc func(ctx context.Context, req *irembopay.InvoiceRequest, expiryDuration time.Duration) (*irembopay.Invoice, error)
+
+	// GetFunc mocks the Get method
+	GetFunc func(ctx context.Context, invoiceReference string) (*irembopay.Invoice, error)
+
+	// ListFunc mocks the List method
+	ListFunc func(ctx context.Context, filter *irembopay.InvoiceFilter) (*irembopay.InvoicePage, error)
+
+	// ListAllFunc mocks the ListAll method
+	ListAllFunc func(ctx context.Context, filter *irembopay.InvoiceFilter) iter.Seq2[*irembopay.Invoice, error]
+
+	// UpdateFunc mocks the Update method
+	UpdateFunc func(ctx context.Context, invoiceNumber string, req *irembopay.UpdateInvoiceRequest) (*irembopay.Invoice, error)
+
+	// UpdateExpiryTimeFunc mocks the UpdateExpiryTime method
+	UpdateExpiryTimeFunc func(ctx context.Context, invoiceNumber string, expiryTime time.Time) (*irembopay.Invoice, error)
+
+	// CancelFunc mocks the Cancel method
+	CancelFunc func(ctx context.Context, invoiceNumber string, reason string) (*irembopay.Invoice, error)
+
+	// WaitForPaymentFunc mocks the WaitForPayment method
+	WaitForPaymentFunc func(ctx context.Context, invoiceNumber string, opts irembopay.WaitOptions) (*irembopay.Invoice, error)
+
+	mu    sync.Mutex
+	calls struct {
+		Create                []InvoiceAPIMockCreateCall
+		CreateWithIdempotency []InvoiceAPIMockCreateWithIdempotencyCall
+		CreateWithExpiry      []InvoiceAPIMockCreateWithExpiryCall
+		Get                   []InvoiceAPIMockGetCall
+		List                  []InvoiceAPIMockListCall
+		ListAll               []InvoiceAPIMockListAllCall
+		Update                []InvoiceAPIMockUpdateCall
+		UpdateExpiryTime      []InvoiceAPIMockUpdateExpiryTimeCall
+		Cancel                []InvoiceAPIMockCancelCall
+		WaitForPayment        []InvoiceAPIMockWaitForPaymentCall
+	}
+}
+
+var _ irembopay.InvoiceAPI = (*InvoiceAPIMock)(nil)
+
+// InvoiceAPIMockCreateCall holds the arguments of a call to InvoiceAPIMock.Create
+type InvoiceAPIMockCreateCall struct {
+	Ctx context.Context
+	Req *irembopay.InvoiceRequest
+}
+
+// Create calls CreateFunc and records the call
+func (m *InvoiceAPIMock) Create(ctx context.Context, req *irembopay.InvoiceRequest) (*irembopay.Invoice, error) {
+	if m.CreateFunc == nil {
+		panic("InvoiceAPIMock.CreateFunc: method is nil but InvoiceAPI.Create was just called")
+	}
+	m.mu.Lock()
+	m.calls.Create = append(m.calls.Create, InvoiceAPIMockCreateCall{Ctx: ctx, Req: req})
+	m.mu.Unlock()
+	return m.CreateFunc(ctx, req)
+}
+
+// CreateCalls returns the calls made to Create
+func (m *InvoiceAPIMock) CreateCalls() []InvoiceAPIMockCreateCall {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]InvoiceAPIMockCreateCall(nil), m.calls.Create...)
+}
+
+// InvoiceAPIMockCreateWithIdempotencyCall holds the arguments of a call to InvoiceAPIMock.CreateWithIdempotency
+type InvoiceAPIMockCreateWithIdempotencyCall struct {
+	Ctx            context.Context
+	Req            *irembopay.InvoiceRequest
+	IdempotencyKey string
+}
+
+// CreateWithIdempotency calls CreateWithIdempotencyFunc and records the call
+func (m *InvoiceAPIMock) CreateWithIdempotency(ctx context.Context, req *irembopay.InvoiceRequest, idempotencyKey string) (*irembopay.Invoice, error) {
+	if m.CreateWithIdempotencyFunc == nil {
+		panic("InvoiceAPIMock.CreateWithIdempotencyFunc: method is nil but InvoiceAPI.CreateWithIdempotency was just called")
+	}
+	m.mu.Lock()
+	m.calls.CreateWithIdempotency = append(m.calls.CreateWithIdempotency, InvoiceAPIMockCreateWithIdempotencyCall{Ctx: ctx, Req: req, IdempotencyKey: idempotencyKey})
+	m.mu.Unlock()
+	return m.CreateWithIdempotencyFunc(ctx, req, idempotencyKey)
+}
+
+// CreateWithIdempotencyCalls returns the calls made to CreateWithIdempotency
+func (m *InvoiceAPIMock) CreateWithIdempotencyCalls() []InvoiceAPIMockCreateWithIdempotencyCall {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]InvoiceAPIMockCreateWithIdempotencyCall(nil), m.calls.CreateWithIdempotency...)
+}
+
+// InvoiceAPIMockCreateWithExpiryCall holds the arguments of a call to InvoiceAPIMock.CreateWithExpiry
+type InvoiceAPIMockCreateWithExpiryCall struct {
+	Ctx            context.Context
+	Req            *irembopay.InvoiceRequest
+	ExpiryDuration time.Duration
+}
+
+// CreateWithExpiry calls CreateWithExpiryFunc and records the call
+func (m *InvoiceAPIMock) CreateWithExpiry(ctx context.Context, req *irembopay.InvoiceRequest, expiryDuration time.Duration) (*irembopay.Invoice, error) {
+	if m.CreateWithExpiryFunc == nil {
+		panic("InvoiceAPIMock.CreateWithExpiryFunc: method is nil but InvoiceAPI.CreateWithExpiry was just called")
+	}
+	m.mu.Lock()
+	m.calls.CreateWithExpiry = append(m.calls.CreateWithExpiry, InvoiceAPIMockCreateWithExpiryCall{Ctx: ctx, Req: req, ExpiryDuration: expiryDuration})
+	m.mu.Unlock()
+	return m.CreateWithExpiryFunc(ctx, req, expiryDuration)
+}
+
+// CreateWithExpiryCalls returns the calls made to CreateWithExpiry
+func (m *InvoiceAPIMock) CreateWithExpiryCalls() []InvoiceAPIMockCreateWithExpiryCall {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]InvoiceAPIMockCreateWithExpiryCall(nil), m.calls.CreateWithExpiry...)
+}
+
+// InvoiceAPIMockGetCall holds the arguments of a call to InvoiceAPIMock.Get
+type InvoiceAPIMockGetCall struct {
+	Ctx              context.Context
+	InvoiceReference string
+}
+
+// Get calls GetFunc and records the call
+func (m *InvoiceAPIMock) Get(ctx context.Context, invoiceReference string) (*irembopay.Invoice, error) {
+	if m.GetFunc == nil {
+		panic("InvoiceAPIMock.GetFunc: method is nil but InvoiceAPI.Get was just called")
+	}
+	m.mu.Lock()
+	m.calls.Get = append(m.calls.Get, InvoiceAPIMockGetCall{Ctx: ctx, InvoiceReference: invoiceReference})
+	m.mu.Unlock()
+	return m.GetFunc(ctx, invoiceReference)
+}
+
+// GetCalls returns the calls made to Get
+func (m *InvoiceAPIMock) GetCalls() []InvoiceAPIMockGetCall {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]InvoiceAPIMockGetCall(nil), m.calls.Get...)
+}
+
+// InvoiceAPIMockListCall holds the arguments of a call to InvoiceAPIMock.List
+type InvoiceAPIMockListCall struct {
+	Ctx    context.Context
+	Filter *irembopay.InvoiceFilter
+}
+
+// List calls ListFunc and records the call
+func (m *InvoiceAPIMock) List(ctx context.Context, filter *irembopay.InvoiceFilter) (*irembopay.InvoicePage, error) {
+	if m.ListFunc == nil {
+		panic("InvoiceAPIMock.ListFunc: method is nil but InvoiceAPI.List was just called")
+	}
+	m.mu.Lock()
+	m.calls.List = append(m.calls.List, InvoiceAPIMockListCall{Ctx: ctx, Filter: filter})
+	m.mu.Unlock()
+	return m.ListFunc(ctx, filter)
+}
+
+// ListCalls returns the calls made to List
+func (m *InvoiceAPIMock) ListCalls() []InvoiceAPIMockListCall {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]InvoiceAPIMockListCall(nil), m.calls.List...)
+}
+
+// InvoiceAPIMockListAllCall holds the arguments of a call to InvoiceAPIMock.ListAll
+type InvoiceAPIMockListAllCall struct {
+	Ctx    context.Context
+	Filter *irembopay.InvoiceFilter
+}
+
+// ListAll calls ListAllFunc and records the call
+func (m *InvoiceAPIMock) ListAll(ctx context.Context, filter *irembopay.InvoiceFilter) iter.Seq2[*irembopay.Invoice, error] {
+	if m.ListAllFunc == nil {
+		panic("InvoiceAPIMock.ListAllFunc: method is nil but InvoiceAPI.ListAll was just called")
+	}
+	m.mu.Lock()
+	m.calls.ListAll = append(m.calls.ListAll, InvoiceAPIMockListAllCall{Ctx: ctx, Filter: filter})
+	m.mu.Unlock()
+	return m.ListAllFunc(ctx, filter)
+}
+
+// ListAllCalls returns the calls made to ListAll
+func (m *InvoiceAPIMock) ListAllCalls() []InvoiceAPIMockListAllCall {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]InvoiceAPIMockListAllCall(nil), m.calls.ListAll...)
+}
+
+// InvoiceAPIMockUpdateCall holds the arguments of a call to InvoiceAPIMock.Update
+type InvoiceAPIMockUpdateCall struct {
+	Ctx           context.Context
+	InvoiceNumber string
+	Req           *irembopay.UpdateInvoiceRequest
+}
+
+// Update calls UpdateFunc and records the call
+func (m *InvoiceAPIMock) Update(ctx context.Context, invoiceNumber string, req *irembopay.UpdateInvoiceRequest) (*irembopay.Invoice, error) {
+	if m.UpdateFunc == nil {
+		panic("InvoiceAPIMock.UpdateFunc: method is nil but InvoiceAPI.Update was just called")
+	}
+	m.mu.Lock()
+	m.calls.Update = append(m.calls.Update, InvoiceAPIMockUpdateCall{Ctx: ctx, InvoiceNumber: invoiceNumber, Req: req})
+	m.mu.Unlock()
+	return m.UpdateFunc(ctx, invoiceNumber, req)
+}
+
+// UpdateCalls returns the calls made to Update
+func (m *InvoiceAPIMock) UpdateCalls() []InvoiceAPIMockUpdateCall {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]InvoiceAPIMockUpdateCall(nil), m.calls.Update...)
+}
+
+// InvoiceAPIMockUpdateExpiryTimeCall holds the arguments of a call to InvoiceAPIMock.UpdateExpiryTime
+type InvoiceAPIMockUpdateExpiryTimeCall struct {
+	Ctx           context.Context
+	InvoiceNumber string
+	ExpiryTime    time.Time
+}
+
+// UpdateExpiryTime calls UpdateExpiryTimeFunc and records the call
+func (m *InvoiceAPIMock) UpdateExpiryTime(ctx context.Context, invoiceNumber string, expiryTime time.Time) (*irembopay.Invoice, error) {
+	if m.UpdateExpiryTimeFunc == nil {
+		panic("InvoiceAPIMock.UpdateExpiryTimeFunc: method is nil but InvoiceAPI.UpdateExpiryTime was just called")
+	}
+	m.mu.Lock()
+	m.calls.UpdateExpiryTime = append(m.calls.UpdateExpiryTime, InvoiceAPIMockUpdateExpiryTimeCall{Ctx: ctx, InvoiceNumber: invoiceNumber, ExpiryTime: expiryTime})
+	m.mu.Unlock()
+	return m.UpdateExpiryTimeFunc(ctx, invoiceNumber, expiryTime)
+}
+
+// UpdateExpiryTimeCalls returns the calls made to UpdateExpiryTime
+func (m *InvoiceAPIMock) UpdateExpiryTimeCalls() []InvoiceAPIMockUpdateExpiryTimeCall {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]InvoiceAPIMockUpdateExpiryTimeCall(nil), m.calls.UpdateExpiryTime...)
+}
+
+// InvoiceAPIMockCancelCall holds the arguments of a call to InvoiceAPIMock.Cancel
+type InvoiceAPIMockCancelCall struct {
+	Ctx           context.Context
+	InvoiceNumber string
+	Reason        string
+}
+
+// Cancel calls CancelFunc and records the call
+func (m *InvoiceAPIMock) Cancel(ctx context.Context, invoiceNumber string, reason string) (*irembopay.Invoice, error) {
+	if m.CancelFunc == nil {
+		panic("InvoiceAPIMock.CancelFunc: method is nil but InvoiceAPI.Cancel was just called")
+	}
+	m.mu.Lock()
+	m.calls.Cancel = append(m.calls.Cancel, InvoiceAPIMockCancelCall{Ctx: ctx, InvoiceNumber: invoiceNumber, Reason: reason})
+	m.mu.Unlock()
+	return m.CancelFunc(ctx, invoiceNumber, reason)
+}
+
+// CancelCalls returns the calls made to Cancel
+func (m *InvoiceAPIMock) CancelCalls() []InvoiceAPIMockCancelCall {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]InvoiceAPIMockCancelCall(nil), m.calls.Cancel...)
+}
+
+// InvoiceAPIMockWaitForPaymentCall holds the arguments of a call to InvoiceAPIMock.WaitForPayment
+type InvoiceAPIMockWaitForPaymentCall struct {
+	Ctx           context.Context
+	InvoiceNumber string
+	Opts          irembopay.WaitOptions
+}
+
+// WaitForPayment calls WaitForPaymentFunc and records the call
+func (m *InvoiceAPIMock) WaitForPayment(ctx context.Context, invoiceNumber string, opts irembopay.WaitOptions) (*irembopay.Invoice, error) {
+	if m.WaitForPaymentFunc == nil {
+		panic("InvoiceAPIMock.WaitForPaymentFunc: method is nil but InvoiceAPI.WaitForPayment was just called")
+	}
+	m.mu.Lock()
+	m.calls.WaitForPayment = append(m.calls.WaitForPayment, InvoiceAPIMockWaitForPaymentCall{Ctx: ctx, InvoiceNumber: invoiceNumber, Opts: opts})
+	m.mu.Unlock()
+	return m.WaitForPaymentFunc(ctx, invoiceNumber, opts)
+}
+
+// WaitForPaymentCalls returns the calls made to WaitForPayment
+func (m *InvoiceAPIMock) WaitForPaymentCalls() []InvoiceAPIMockWaitForPaymentCall {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]InvoiceAPIMockWaitForPaymentCall(nil), m.calls.WaitForPayment...)
+}
+
+// BatchAPIMock is a mock implementation of irembopay.BatchAPI
+type BatchAPIMock struct {
+	// CreateFunc mocks the Create method
+	CreateFunc func(ctx context.Context, req *irembopay.BatchInvoiceRequest) (*irembopay.Invoice, error)
+
+	// CreateWithIdempotencyFunc mocks the CreateWithIdempotency method
+	CreateWithIdempotencyFunc func(ctx context.Context, req *irembopay.BatchInvoiceRequest, idempotencyKey string) (*irembopay.Invoice, error)
+
+	mu    sync.Mutex
+	calls struct {
+		Create                []BatchAPIMockCreateCall
+		CreateWithIdempotency []BatchAPIMockCreateWithIdempotencyCall
+	}
+}
+
+var _ irembopay.BatchAPI = (*BatchAPIMock)(nil)
+
+// BatchAPIMockCreateCall holds the arguments of a call to BatchAPIMock.Create
+type BatchAPIMockCreateCall struct {
+	Ctx context.Context
+	Req *irembopay.BatchInvoiceRequest
+}
+
+// Create calls CreateFunc and records the call
+func (m *BatchAPIMock) Create(ctx context.Context, req *irembopay.BatchInvoiceRequest) (*irembopay.Invoice, error) {
+	if m.CreateFunc == nil {
+		panic("BatchAPIMock.CreateFunc: method is nil but BatchAPI.Create was just called")
+	}
+	m.mu.Lock()
+	m.calls.Create = append(m.calls.Create, BatchAPIMockCreateCall{Ctx: ctx, Req: req})
+	m.mu.Unlock()
+	return m.CreateFunc(ctx, req)
+}
+
+// CreateCalls returns the calls made to Create
+func (m *BatchAPIMock) CreateCalls() []BatchAPIMockCreateCall {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]BatchAPIMockCreateCall(nil), m.calls.Create...)
+}
+
+// BatchAPIMockCreateWithIdempotencyCall holds the arguments of a call to BatchAPIMock.CreateWithIdempotency
+type BatchAPIMockCreateWithIdempotencyCall struct {
+	Ctx            context.Context
+	Req            *irembopay.BatchInvoiceRequest
+	IdempotencyKey string
+}
+
+// CreateWithIdempotency calls CreateWithIdempotencyFunc and records the call
+func (m *BatchAPIMock) CreateWithIdempotency(ctx context.Context, req *irembopay.BatchInvoiceRequest, idempotencyKey string) (*irembopay.Invoice, error) {
+	if m.CreateWithIdempotencyFunc == nil {
+		panic("BatchAPIMock.CreateWithIdempotencyFunc: method is nil but BatchAPI.CreateWithIdempotency was just called")
+	}
+	m.mu.Lock()
+	m.calls.CreateWithIdempotency = append(m.calls.CreateWithIdempotency, BatchAPIMockCreateWithIdempotencyCall{Ctx: ctx, Req: req, IdempotencyKey: idempotencyKey})
+	m.mu.Unlock()
+	return m.CreateWithIdempotencyFunc(ctx, req, idempotencyKey)
+}
+
+// CreateWithIdempotencyCalls returns the calls made to CreateWithIdempotency
+func (m *BatchAPIMock) CreateWithIdempotencyCalls() []BatchAPIMockCreateWithIdempotencyCall {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]BatchAPIMockCreateWithIdempotencyCall(nil), m.calls.CreateWithIdempotency...)
+}
+
+// PaymentAPIMock is a mock implementation of irembopay.PaymentAPI
+type PaymentAPIMock struct {
+	// InitiateMomoPaymentFunc mocks the InitiateMomoPayment method
+	InitiateMomoPaymentFunc func(ctx context.Context, req *irembopay.MomoPaymentRequest) (*irembopay.MomoPaymentResponse, error)
+
+	// InitiateMomoPaymentAutoDetectFunc mocks the InitiateMomoPaymentAutoDetect method
+	InitiateMomoPaymentAutoDetectFunc func(ctx context.Context, req *irembopay.MomoPaymentRequest) (*irembopay.MomoPaymentResponse, error)
+
+	// VerifyWebhookSignatureFunc mocks the VerifyWebhookSignature method
+	VerifyWebhookSignatureFunc func(signature string, payload string) (bool, error)
+
+	// MatchWebhookSignatureFunc mocks the MatchWebhookSignature method
+	MatchWebhookSignatureFunc func(signature string, payload string) (int, bool, error)
+
+	// ParseNotificationFunc mocks the ParseNotification method
+	ParseNotificationFunc func(payload string) (*irembopay.PaymentNotification, error)
+
+	// HandleWebhookFunc mocks the HandleWebhook method
+	HandleWebhookFunc func(signature string, payload string) (*irembopay.PaymentNotification, error)
+
+	// ReleaseWebhookFunc mocks the ReleaseWebhook method
+	ReleaseWebhookFunc func(ctx context.Context, signature string) error
+
+	// ValidateWebhookTimestampFunc mocks the ValidateWebhookTimestamp method
+	ValidateWebhookTimestampFunc func(signature string, maxAge time.Duration) (bool, error)
+
+	// WebhookHandlerFunc mocks the WebhookHandler method
+	WebhookHandlerFunc func(opts irembopay.WebhookOptions) http.Handler
+
+	mu    sync.Mutex
+	calls struct {
+		InitiateMomoPayment           []PaymentAPIMockInitiateMomoPaymentCall
+		InitiateMomoPaymentAutoDetect []PaymentAPIMockInitiateMomoPaymentAutoDetectCall
+		VerifyWebhookSignature        []PaymentAPIMockVerifyWebhookSignatureCall
+		MatchWebhookSignature         []PaymentAPIMockMatchWebhookSignatureCall
+		ParseNotification             []PaymentAPIMockParseNotificationCall
+		HandleWebhook                 []PaymentAPIMockHandleWebhookCall
+		ReleaseWebhook                []PaymentAPIMockReleaseWebhookCall
+		ValidateWebhookTimestamp      []PaymentAPIMockValidateWebhookTimestampCall
+		WebhookHandler                []PaymentAPIMockWebhookHandlerCall
+	}
+}
+
+var _ irembopay.PaymentAPI = (*PaymentAPIMock)(nil)
+
+// PaymentAPIMockInitiateMomoPaymentCall holds the arguments of a call to PaymentAPIMock.InitiateMomoPayment
+type PaymentAPIMockInitiateMomoPaymentCall struct {
+	Ctx context.Context
+	Req *irembopay.MomoPaymentRequest
+}
+
+// InitiateMomoPayment calls InitiateMomoPaymentFunc and records the call
+func (m *PaymentAPIMock) InitiateMomoPayment(ctx context.Context, req *irembopay.MomoPaymentRequest) (*irembopay.MomoPaymentResponse, error) {
+	if m.InitiateMomoPaymentFunc == nil {
+		panic("PaymentAPIMock.InitiateMomoPaymentFunc: method is nil but PaymentAPI.InitiateMomoPayment was just called")
+	}
+	m.mu.Lock()
+	m.calls.InitiateMomoPayment = append(m.calls.InitiateMomoPayment, PaymentAPIMockInitiateMomoPaymentCall{Ctx: ctx, Req: req})
+	m.mu.Unlock()
+	return m.InitiateMomoPaymentFunc(ctx, req)
+}
+
+// InitiateMomoPaymentCalls returns the calls made to InitiateMomoPayment
+func (m *PaymentAPIMock) InitiateMomoPaymentCalls() []PaymentAPIMockInitiateMomoPaymentCall {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]PaymentAPIMockInitiateMomoPaymentCall(nil), m.calls.InitiateMomoPayment...)
+}
+
+// PaymentAPIMockInitiateMomoPaymentAutoDetectCall holds the arguments of a call to PaymentAPIMock.InitiateMomoPaymentAutoDetect
+type PaymentAPIMockInitiateMomoPaymentAutoDetectCall struct {
+	Ctx context.Context
+	Req *irembopay.MomoPaymentRequest
+}
+
+// InitiateMomoPaymentAutoDetect calls InitiateMomoPaymentAutoDetectFunc and records the call
+func (m *PaymentAPIMock) InitiateMomoPaymentAutoDetect(ctx context.Context, req *irembopay.MomoPaymentRequest) (*irembopay.MomoPaymentResponse, error) {
+	if m.InitiateMomoPaymentAutoDetectFunc == nil {
+		panic("PaymentAPIMock.InitiateMomoPaymentAutoDetectFunc: method is nil but PaymentAPI.InitiateMomoPaymentAutoDetect was just called")
+	}
+	m.mu.Lock()
+	m.calls.InitiateMomoPaymentAutoDetect = append(m.calls.InitiateMomoPaymentAutoDetect, PaymentAPIMockInitiateMomoPaymentAutoDetectCall{Ctx: ctx, Req: req})
+	m.mu.Unlock()
+	return m.InitiateMomoPaymentAutoDetectFunc(ctx, req)
+}
+
+// InitiateMomoPaymentAutoDetectCalls returns the calls made to InitiateMomoPaymentAutoDetect
+func (m *PaymentAPIMock) InitiateMomoPaymentAutoDetectCalls() []PaymentAPIMockInitiateMomoPaymentAutoDetectCall {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]PaymentAPIMockInitiateMomoPaymentAutoDetectCall(nil), m.calls.InitiateMomoPaymentAutoDetect...)
+}
+
+// PaymentAPIMockVerifyWebhookSignatureCall holds the arguments of a call to PaymentAPIMock.VerifyWebhookSignature
+type PaymentAPIMockVerifyWebhookSignatureCall struct {
+	Signature string
+	Payload   string
+}
+
+// VerifyWebhookSignature calls VerifyWebhookSignatureFunc and records the call
+func (m *PaymentAPIMock) VerifyWebhookSignature(signature string, payload string) (bool, error) {
+	if m.VerifyWebhookSignatureFunc == nil {
+		panic("PaymentAPIMock.VerifyWebhookSignatureFunc: method is nil but PaymentAPI.VerifyWebhookSignature was just called")
+	}
+	m.mu.Lock()
+	m.calls.VerifyWebhookSignature = append(m.calls.VerifyWebhookSignature, PaymentAPIMockVerifyWebhookSignatureCall{Signature: signature, Payload: payload})
+	m.mu.Unlock()
+	return m.VerifyWebhookSignatureFunc(signature, payload)
+}
+
+// VerifyWebhookSignatureCalls returns the calls made to VerifyWebhookSignature
+func (m *PaymentAPIMock) VerifyWebhookSignatureCalls() []PaymentAPIMockVerifyWebhookSignatureCall {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]PaymentAPIMockVerifyWebhookSignatureCall(nil), m.calls.VerifyWebhookSignature...)
+}
+
+// PaymentAPIMockMatchWebhookSignatureCall holds the arguments of a call to PaymentAPIMock.MatchWebhookSignature
+type PaymentAPIMockMatchWebhookSignatureCall struct {
+	Signature string
+	Payload   string
+}
+
+// MatchWebhookSignature calls MatchWebhookSignatureFunc and records the call
+func (m *PaymentAPIMock) MatchWebhookSignature(signature string, payload string) (int, bool, error) {
+	if m.MatchWebhookSignatureFunc == nil {
+		panic("PaymentAPIMock.MatchWebhookSignatureFunc: method is nil but PaymentAPI.MatchWebhookSignature was just called")
+	}
+	m.mu.Lock()
+	m.calls.MatchWebhookSignature = append(m.calls.MatchWebhookSignature, PaymentAPIMockMatchWebhookSignatureCall{Signature: signature, Payload: payload})
+	m.mu.Unlock()
+	return m.MatchWebhookSignatureFunc(signature, payload)
+}
+
+// MatchWebhookSignatureCalls returns the calls made to MatchWebhookSignature
+func (m *PaymentAPIMock) MatchWebhookSignatureCalls() []PaymentAPIMockMatchWebhookSignatureCall {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]PaymentAPIMockMatchWebhookSignatureCall(nil), m.calls.MatchWebhookSignature...)
+}
+
+// PaymentAPIMockParseNotificationCall holds the arguments of a call to PaymentAPIMock.ParseNotification
+type PaymentAPIMockParseNotificationCall struct {
+	Payload string
+}
+
+// ParseNotification calls ParseNotificationFunc and records the call
+func (m *PaymentAPIMock) ParseNotification(payload string) (*irembopay.PaymentNotification, error) {
+	if m.ParseNotificationFunc == nil {
+		panic("PaymentAPIMock.ParseNotificationFunc: method is nil but PaymentAPI.ParseNotification was just called")
+	}
+	m.mu.Lock()
+	m.calls.ParseNotification = append(m.calls.ParseNotification, PaymentAPIMockParseNotificationCall{Payload: payload})
+	m.mu.Unlock()
+	return m.ParseNotificationFunc(payload)
+}
+
+// ParseNotificationCalls returns the calls made to ParseNotification
+func (m *PaymentAPIMock) ParseNotificationCalls() []PaymentAPIMockParseNotificationCall {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]PaymentAPIMockParseNotificationCall(nil), m.calls.ParseNotification...)
+}
+
+// PaymentAPIMockHandleWebhookCall holds the arguments of a call to PaymentAPIMock.HandleWebhook
+type PaymentAPIMockHandleWebhookCall struct {
+	Signature string
+	Payload   string
+}
+
+// HandleWebhook calls HandleWebhookFunc and records the call
+func (m *PaymentAPIMock) HandleWebhook(signature string, payload string) (*irembopay.PaymentNotification, error) {
+	if m.HandleWebhookFunc == nil {
+		panic("PaymentAPIMock.HandleWebhookFunc: method is nil but PaymentAPI.HandleWebhook was just called")
+	}
+	m.mu.Lock()
+	m.calls.HandleWebhook = append(m.calls.HandleWebhook, PaymentAPIMockHandleWebhookCall{Signature: signature, Payload: payload})
+	m.mu.Unlock()
+	return m.HandleWebhookFunc(signature, payload)
+}
+
+// HandleWebhookCalls returns the calls made to HandleWebhook
+func (m *PaymentAPIMock) HandleWebhookCalls() []PaymentAPIMockHandleWebhookCall {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]PaymentAPIMockHandleWebhookCall(nil), m.calls.HandleWebhook...)
+}
+
+// PaymentAPIMockReleaseWebhookCall holds the arguments of a call to PaymentAPIMock.ReleaseWebhook
+type PaymentAPIMockReleaseWebhookCall struct {
+	Ctx       context.Context
+	Signature string
+}
+
+// ReleaseWebhook calls ReleaseWebhookFunc and records the call
+func (m *PaymentAPIMock) ReleaseWebhook(ctx context.Context, signature string) error {
+	if m.ReleaseWebhookFunc == nil {
+		panic("PaymentAPIMock.ReleaseWebhookFunc: method is nil but PaymentAPI.ReleaseWebhook was just called")
+	}
+	m.mu.Lock()
+	m.calls.ReleaseWebhook = append(m.calls.ReleaseWebhook, PaymentAPIMockReleaseWebhookCall{Ctx: ctx, Signature: signature})
+	m.mu.Unlock()
+	return m.ReleaseWebhookFunc(ctx, signature)
+}
+
+// ReleaseWebhookCalls returns the calls made to ReleaseWebhook
+func (m *PaymentAPIMock) ReleaseWebhookCalls() []PaymentAPIMockReleaseWebhookCall {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]PaymentAPIMockReleaseWebhookCall(nil), m.calls.ReleaseWebhook...)
+}
+
+// PaymentAPIMockValidateWebhookTimestampCall holds the arguments of a call to PaymentAPIMock.ValidateWebhookTimestamp
+type PaymentAPIMockValidateWebhookTimestampCall struct {
+	Signature string
+	MaxAge    time.Duration
+}
+
+// ValidateWebhookTimestamp calls ValidateWebhookTimestampFunc and records the call
+func (m *PaymentAPIMock) ValidateWebhookTimestamp(signature string, maxAge time.Duration) (bool, error) {
+	if m.ValidateWebhookTimestampFunc == nil {
+		panic("PaymentAPIMock.ValidateWebhookTimestampFunc: method is nil but PaymentAPI.ValidateWebhookTimestamp was just called")
+	}
+	m.mu.Lock()
+	m.calls.ValidateWebhookTimestamp = append(m.calls.ValidateWebhookTimestamp, PaymentAPIMockValidateWebhookTimestampCall{Signature: signature, MaxAge: maxAge})
+	m.mu.Unlock()
+	return m.ValidateWebhookTimestampFunc(signature, maxAge)
+}
+
+// ValidateWebhookTimestampCalls returns the calls made to ValidateWebhookTimestamp
+func (m *PaymentAPIMock) ValidateWebhookTimestampCalls() []PaymentAPIMockValidateWebhookTimestampCall {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]PaymentAPIMockValidateWebhookTimestampCall(nil), m.calls.ValidateWebhookTimestamp...)
+}
+
+// PaymentAPIMockWebhookHandlerCall holds the arguments of a call to PaymentAPIMock.WebhookHandler
+type PaymentAPIMockWebhookHandlerCall struct {
+	Opts irembopay.WebhookOptions
+}
+
+// WebhookHandler calls WebhookHandlerFunc and records the call
+func (m *PaymentAPIMock) WebhookHandler(opts irembopay.WebhookOptions) http.Handler {
+	if m.WebhookHandlerFunc == nil {
+		panic("PaymentAPIMock.WebhookHandlerFunc: method is nil but PaymentAPI.WebhookHandler was just called")
+	}
+	m.mu.Lock()
+	m.calls.WebhookHandler = append(m.calls.WebhookHandler, PaymentAPIMockWebhookHandlerCall{Opts: opts})
+	m.mu.Unlock()
+	return m.WebhookHandlerFunc(opts)
+}
+
+// WebhookHandlerCalls returns the calls made to WebhookHandler
+func (m *PaymentAPIMock) WebhookHandlerCalls() []PaymentAPIMockWebhookHandlerCall {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]PaymentAPIMockWebhookHandlerCall(nil), m.calls.WebhookHandler...)
+}
+
+// CheckoutAPIMock is a mock implementation of irembopay.CheckoutAPI
+type CheckoutAPIMock struct {
+	// PushToPayFunc mocks the PushToPay method
+	PushToPayFunc func(ctx context.Context, req *irembopay.CheckoutRequest) (*irembopay.Checkout, error)
+
+	// ResumeFunc mocks the Resume method
+	ResumeFunc func(ctx context.Context, req *irembopay.CheckoutRequest, checkout *irembopay.Checkout) (*irembopay.Checkout, error)
+
+	mu    sync.Mutex
+	calls struct {
+		PushToPay []CheckoutAPIMockPushToPayCall
+		Resume    []CheckoutAPIMockResumeCall
+	}
+}
+
+var _ irembopay.CheckoutAPI = (*CheckoutAPIMock)(nil)
+
+// CheckoutAPIMockPushToPayCall holds the arguments of a call to CheckoutAPIMock.PushToPay
+type CheckoutAPIMockPushToPayCall struct {
+	Ctx context.Context
+	Req *irembopay.CheckoutRequest
+}
+
+// PushToPay calls PushToPayFunc and records the call
+func (m *CheckoutAPIMock) PushToPay(ctx context.Context, req *irembopay.CheckoutRequest) (*irembopay.Checkout, error) {
+	if m.PushToPayFunc == nil {
+		panic("CheckoutAPIMock.PushToPayFunc: method is nil but CheckoutAPI.PushToPay was just called")
+	}
+	m.mu.Lock()
+	m.calls.PushToPay = append(m.calls.PushToPay, CheckoutAPIMockPushToPayCall{Ctx: ctx, Req: req})
+	m.mu.Unlock()
+	return m.PushToPayFunc(ctx, req)
+}
+
+// PushToPayCalls returns the calls made to PushToPay
+func (m *CheckoutAPIMock) PushToPayCalls() []CheckoutAPIMockPushToPayCall {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]CheckoutAPIMockPushToPayCall(nil), m.calls.PushToPay...)
+}
+
+// CheckoutAPIMockResumeCall holds the arguments of a call to CheckoutAPIMock.Resume
+type CheckoutAPIMockResumeCall struct {
+	Ctx      context.Context
+	Req      *irembopay.CheckoutRequest
+	Checkout *irembopay.Checkout
+}
+
+// Resume calls ResumeFunc and records the call
+func (m *CheckoutAPIMock) Resume(ctx context.Context, req *irembopay.CheckoutRequest, checkout *irembopay.Checkout) (*irembopay.Checkout, error) {
+	if m.ResumeFunc == nil {
+		panic("CheckoutAPIMock.ResumeFunc: method is nil but CheckoutAPI.Resume was just called")
+	}
+	m.mu.Lock()
+	m.calls.Resume = append(m.calls.Resume, CheckoutAPIMockResumeCall{Ctx: ctx, Req: req, Checkout: checkout})
+	m.mu.Unlock()
+	return m.ResumeFunc(ctx, req, checkout)
+}
+
+// ResumeCalls returns the calls made to Resume
+func (m *CheckoutAPIMock) ResumeCalls() []CheckoutAPIMockResumeCall {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]CheckoutAPIMockResumeCall(nil), m.calls.Resume...)
+}
@@ -0,0 +1,660 @@
+// Package irembopaytest provides an in-memory IremboPay API server for tests.
+//
+// The server implements the invoice, batch invoice and mobile money endpoints
+// with realistic state, so integrations can be exercised without reaching the
+// IremboPay sandbox:
+//
+//	srv := irembopaytest.NewServer("test-secret-key")
+//	defer srv.Close()
+//
+//	client, err := srv.NewClient()
+//
+// It also provides mock implementations of the service interfaces for unit
+// tests that should not perform any HTTP calls.
+package irembopaytest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/cruso003/irembopay"
+	"github.com/cruso003/irembopay/phone"
+)
+
+// Server is a fake IremboPay API backed by an httptest.Server
+type Server struct {
+	server    *httptest.Server
+	secretKey string
+
+	mu            sync.Mutex
+	now           func() time.Time
+	invoices      map[string]*irembopay.Invoice // Invoices by invoice number
+	transactions  map[string]string             // Invoice numbers by transaction ID
+	idempotency   map[string]recordedResponse   // Responses by idempotency key
+	payments      map[string]*irembopay.MomoPaymentResponse
+	nextInvoice   int64
+	nextReference int64
+	nextRequest   atomic.Int64 // Sequence of the X-Request-Id response header
+}
+
+// recordedResponse is a response stored for idempotent replays
+type recordedResponse struct {
+	statusCode int
+	body       []byte
+}
+
+// NewServer starts a new fake IremboPay server accepting the given secret key
+func NewServer(secretKey string) *Server {
+	s := &Server{
+		secretKey:    secretKey,
+		now:          time.Now,
+		invoices:     make(map[string]*irembopay.Invoice),
+		transactions: make(map[string]string),
+		idempotency:  make(map[string]recordedResponse),
+		payments:     make(map[string]*irembopay.MomoPaymentResponse),
+		nextInvoice:  880419623157,
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /payments/invoices", s.handleListInvoices)
+	mux.HandleFunc("POST /payments/invoices", s.handleCreateInvoice)
+	mux.HandleFunc("POST /payments/invoices/batch", s.handleCreateBatch)
+	mux.HandleFunc("GET /payments/invoices/{reference}", s.handleGetInvoice)
+	mux.HandleFunc("PUT /payments/invoices/{reference}", s.handleUpdateInvoice)
+	mux.HandleFunc("POST /payments/invoices/{reference}/cancel", s.handleCancelInvoice)
+	mux.HandleFunc("POST /payments/transactions/initiate", s.handleInitiateMomo)
+
+	s.server = httptest.NewTLSServer(s.authenticate(mux))
+	return s
+}
+
+// Close shuts down the server
+func (s *Server) Close() {
+	s.server.Close()
+}
+
+// URL returns the base URL of the server
+func (s *Server) URL() string {
+	return s.server.URL
+}
+
+// SecretKey returns the secret key accepted by the server
+func (s *Server) SecretKey() string {
+	return s.secretKey
+}
+
+// Options returns the configuration options pointing a client at the server
+func (s *Server) Options() []irembopay.ConfigOption {
+	u, _ := url.Parse(s.server.URL)
+	return []irembopay.ConfigOption{
+		irembopay.WithBaseURL(u),
+		irembopay.WithHTTPClient(s.server.Client()),
+	}
+}
+
+// NewClient creates an IremboPay client connected to the server.
+// Additional options are applied after the server options.
+func (s *Server) NewClient(opts ...irembopay.ConfigOption) (*irembopay.IremboPay, error) {
+	opts = append(s.Options(), opts...)
+	config, err := irembopay.NewConfig(irembopay.Sandbox, s.secretKey, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return irembopay.NewIremboPay(config), nil
+}
+
+// SetClock sets the function used by the server to read the current time
+func (s *Server) SetClock(now func() time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.now = now
+}
+
+// Invoice returns a copy of the invoice with the given number or transaction ID
+func (s *Server) Invoice(reference string) (*irembopay.Invoice, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	invoice, ok := s.lookup(reference)
+	if !ok {
+		return nil, false
+	}
+	return copyInvoice(invoice), true
+}
+
+// Invoices returns copies of all invoices known to the server
+func (s *Server) Invoices() []*irembopay.Invoice {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	invoices := make([]*irembopay.Invoice, 0, len(s.invoices))
+	for _, invoice := range s.invoices {
+		invoices = append(invoices, copyInvoice(invoice))
+	}
+	return invoices
+}
+
+// authenticate rejects requests without the expected secret key
+func (s *Server) authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Request-Id", fmt.Sprintf("req-%d", s.nextRequest.Add(1)))
+		if r.Header.Get("irembopay-secretKey") != s.secretKey {
+			writeError(w, http.StatusUnauthorized, "Invalid secret key")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// idempotent replays the recorded response for a repeated idempotency key,
+// or records the response produced by the handler
+func (s *Server) idempotent(w http.ResponseWriter, r *http.Request, handle func() (int, interface{})) {
+	key := r.Header.Get("X-Idempotency-Key")
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if key != "" {
+		if recorded, ok := s.idempotency[key]; ok {
+			writeRaw(w, recorded.statusCode, recorded.body)
+			return
+		}
+	}
+
+	statusCode, body := handle()
+	encoded := encodeResponse(statusCode, body)
+	if key != "" && statusCode < 500 {
+		s.idempotency[key] = recordedResponse{statusCode: statusCode, body: encoded}
+	}
+	writeRaw(w, statusCode, encoded)
+}
+
+func (s *Server) handleCreateInvoice(w http.ResponseWriter, r *http.Request) {
+	var req irembopay.InvoiceRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "Malformed request body")
+		return
+	}
+
+	s.idempotent(w, r, func() (int, interface{}) {
+		if msg := validateInvoiceRequest(&req); msg != "" {
+			return http.StatusBadRequest, msg
+		}
+		if _, ok := s.transactions[req.TransactionID]; ok {
+			return http.StatusConflict, fmt.Sprintf("Duplicate transaction id: %s", req.TransactionID)
+		}
+
+		now := s.now()
+		invoice := &irembopay.Invoice{
+			InvoiceNumber:            s.newInvoiceNumber(),
+			TransactionID:            req.TransactionID,
+			CreatedAt:                irembopay.FormatTime(now),
+			ExpiryAt:                 req.ExpiryAt,
+			PaymentAccountIdentifier: req.PaymentAccountIdentifier,
+			PaymentItems:             append([]irembopay.PaymentItem(nil), req.PaymentItems...),
+			Description:              req.Description,
+			Type:                     irembopay.InvoiceTypeSingle,
+			PaymentStatus:            irembopay.PaymentStatusNew,
+			Currency:                 currencyOf(req.PaymentAccountIdentifier),
+			Customer:                 req.Customer,
+			Language:                 req.Language,
+		}
+		invoice.Amount = totalAmount(invoice.PaymentItems)
+		invoice.PaymentLinkUrl = s.paymentLink(invoice.InvoiceNumber)
+
+		s.store(invoice)
+		return http.StatusCreated, copyInvoice(invoice)
+	})
+}
+
+func (s *Server) handleCreateBatch(w http.ResponseWriter, r *http.Request) {
+	var req irembopay.BatchInvoiceRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "Malformed request body")
+		return
+	}
+
+	s.idempotent(w, r, func() (int, interface{}) {
+		if req.TransactionID == "" {
+			return http.StatusBadRequest, "transactionId is required"
+		}
+		if len(req.InvoiceNumbers) < 2 {
+			return http.StatusBadRequest, "A batch requires at least two invoices"
+		}
+		if _, ok := s.transactions[req.TransactionID]; ok {
+			return http.StatusConflict, fmt.Sprintf("Duplicate transaction id: %s", req.TransactionID)
+		}
+
+		// Check every child invoice before linking any of them
+		var children []*irembopay.Invoice
+		seen := make(map[string]bool)
+		for _, number := range req.InvoiceNumbers {
+			if seen[number] {
+				return http.StatusBadRequest, fmt.Sprintf("Duplicate invoice in batch: %s", number)
+			}
+			seen[number] = true
+
+			child, ok := s.invoices[number]
+			if !ok {
+				return http.StatusNotFound, fmt.Sprintf("Invoice not found: %s", number)
+			}
+			if child.PaymentStatus != irembopay.PaymentStatusNew || child.BatchNumber != "" {
+				return http.StatusBadRequest, fmt.Sprintf("Invoice cannot be batched: %s", number)
+			}
+			if s.expired(child) {
+				return http.StatusBadRequest, fmt.Sprintf("Invoice has expired: %s", number)
+			}
+			if len(children) > 0 && child.Currency != children[0].Currency {
+				return http.StatusBadRequest, "Batched invoices must share the same currency"
+			}
+			children = append(children, child)
+		}
+
+		now := s.now()
+		batch := &irembopay.Invoice{
+			InvoiceNumber:            s.newInvoiceNumber(),
+			TransactionID:            req.TransactionID,
+			CreatedAt:                irembopay.FormatTime(now),
+			PaymentAccountIdentifier: children[0].PaymentAccountIdentifier,
+			Description:              req.Description,
+			Type:                     irembopay.InvoiceTypeBatch,
+			PaymentStatus:            irembopay.PaymentStatusNew,
+			Currency:                 children[0].Currency,
+			ChildInvoices:            append([]string(nil), req.InvoiceNumbers...),
+		}
+		for _, child := range children {
+			batch.Amount += child.Amount
+			batch.PaymentItems = append(batch.PaymentItems, child.PaymentItems...)
+			child.BatchNumber = batch.InvoiceNumber
+			child.UpdatedAt = irembopay.FormatTime(now)
+		}
+		batch.BatchNumber = batch.InvoiceNumber
+		batch.PaymentLinkUrl = s.paymentLink(batch.InvoiceNumber)
+
+		s.store(batch)
+		return http.StatusCreated, copyInvoice(batch)
+	})
+}
+
+func (s *Server) handleGetInvoice(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	invoice, ok := s.lookup(r.PathValue("reference"))
+	if !ok {
+		writeError(w, http.StatusNotFound, "Invoice not found")
+		return
+	}
+	writeData(w, http.StatusOK, copyInvoice(invoice))
+}
+
+func (s *Server) handleListInvoices(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	page, err := queryInt(query, "page", 1)
+	if err != nil || page < 1 {
+		writeError(w, http.StatusBadRequest, "page must be a positive integer")
+		return
+	}
+	size, err := queryInt(query, "size", irembopay.DefaultPageSize)
+	if err != nil || size < 1 {
+		writeError(w, http.StatusBadRequest, "size must be a positive integer")
+		return
+	}
+
+	var createdFrom, createdTo time.Time
+	for name, t := range map[string]*time.Time{"createdFrom": &createdFrom, "createdTo": &createdTo} {
+		if value := query.Get(name); value != "" {
+			if *t, err = irembopay.ParseTime(value); err != nil {
+				writeError(w, http.StatusBadRequest, name+" must be an RFC3339 time")
+				return
+			}
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var matches []irembopay.Invoice
+	for _, invoice := range s.invoices {
+		createdAt, _ := irembopay.ParseTime(invoice.CreatedAt)
+		switch {
+		case !createdFrom.IsZero() && createdAt.Before(createdFrom),
+			!createdTo.IsZero() && !createdAt.Before(createdTo),
+			!matchQuery(query, "paymentStatus", string(invoice.PaymentStatus)),
+			!matchQuery(query, "paymentAccountIdentifier", invoice.PaymentAccountIdentifier),
+			!matchCustomer(query, invoice.Customer):
+			continue
+		}
+		matches = append(matches, *copyInvoice(invoice))
+	}
+
+	// Invoice numbers are allocated in creation order
+	sort.Slice(matches, func(i, j int) bool {
+		return matches[i].InvoiceNumber < matches[j].InvoiceNumber
+	})
+
+	result := irembopay.InvoicePage{
+		Invoices:   []irembopay.Invoice{},
+		Page:       page,
+		PageSize:   size,
+		TotalItems: len(matches),
+		TotalPages: (len(matches) + size - 1) / size,
+	}
+	if start := (page - 1) * size; start < len(matches) {
+		result.Invoices = matches[start:min(start+size, len(matches))]
+	}
+
+	writeData(w, http.StatusOK, result)
+}
+
+func (s *Server) handleUpdateInvoice(w http.ResponseWriter, r *http.Request) {
+	var req irembopay.UpdateInvoiceRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "Malformed request body")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	invoice, ok := s.invoices[r.PathValue("reference")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Invoice not found")
+		return
+	}
+	if invoice.PaymentStatus != irembopay.PaymentStatusNew {
+		writeError(w, http.StatusBadRequest, "Only new invoices can be updated")
+		return
+	}
+
+	if req.ExpiryAt != "" {
+		if _, err := irembopay.ParseTime(req.ExpiryAt); err != nil {
+			writeError(w, http.StatusBadRequest, "expiryAt must be an RFC3339 time")
+			return
+		}
+		invoice.ExpiryAt = req.ExpiryAt
+	}
+	if len(req.PaymentItems) > 0 {
+		if msg := validatePaymentItems(req.PaymentItems); msg != "" {
+			writeError(w, http.StatusBadRequest, msg)
+			return
+		}
+		invoice.PaymentItems = append([]irembopay.PaymentItem(nil), req.PaymentItems...)
+		invoice.Amount = totalAmount(invoice.PaymentItems)
+	}
+	invoice.UpdatedAt = irembopay.FormatTime(s.now())
+
+	writeData(w, http.StatusOK, copyInvoice(invoice))
+}
+
+func (s *Server) handleCancelInvoice(w http.ResponseWriter, r *http.Request) {
+	var req irembopay.CancelInvoiceRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "Malformed request body")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	invoice, ok := s.invoices[r.PathValue("reference")]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Invoice not found")
+		return
+	}
+
+	switch invoice.PaymentStatus {
+	case irembopay.PaymentStatusPaid:
+		writeError(w, http.StatusConflict, "Invoice has already been paid")
+		return
+	case irembopay.PaymentStatusNew:
+		now := irembopay.FormatTime(s.now())
+		invoice.PaymentStatus = irembopay.PaymentStatusCancelled
+		invoice.CancelledAt = now
+		invoice.CancellationReason = req.Reason
+		invoice.UpdatedAt = now
+	}
+
+	writeData(w, http.StatusOK, copyInvoice(invoice))
+}
+
+func (s *Server) handleInitiateMomo(w http.ResponseWriter, r *http.Request) {
+	var req irembopay.MomoPaymentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "Malformed request body")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if req.AccountIdentifier == "" {
+		writeError(w, http.StatusBadRequest, "accountIdentifier is required")
+		return
+	}
+	if !req.PaymentProvider.IsValid() {
+		writeError(w, http.StatusBadRequest, "paymentProvider must be MTN or AIRTEL")
+		return
+	}
+
+	invoice, ok := s.invoices[req.InvoiceNumber]
+	if !ok {
+		writeError(w, http.StatusNotFound, "Invoice not found")
+		return
+	}
+	switch invoice.PaymentStatus {
+	case irembopay.PaymentStatusPaid:
+		writeError(w, http.StatusBadRequest, "Invoice is already paid")
+		return
+	case irembopay.PaymentStatusCancelled:
+		writeError(w, http.StatusBadRequest, "Invoice has been cancelled")
+		return
+	}
+	if s.expired(invoice) {
+		writeError(w, http.StatusBadRequest, "Invoice has expired")
+		return
+	}
+
+	s.nextReference++
+	payment := &irembopay.MomoPaymentResponse{
+		AccountIdentifier: req.AccountIdentifier,
+		PaymentProvider:   req.PaymentProvider,
+		InvoiceNumber:     invoice.InvoiceNumber,
+		Amount:            invoice.Amount,
+		ReferenceID:       fmt.Sprintf("MOMO-%08d", s.nextReference),
+	}
+	s.payments[invoice.InvoiceNumber] = payment
+
+	writeData(w, http.StatusOK, payment)
+}
+
+// store saves a new invoice
+func (s *Server) store(invoice *irembopay.Invoice) {
+	s.invoices[invoice.InvoiceNumber] = invoice
+	s.transactions[invoice.TransactionID] = invoice.InvoiceNumber
+}
+
+// lookup finds an invoice by its number or transaction ID
+func (s *Server) lookup(reference string) (*irembopay.Invoice, bool) {
+	if invoice, ok := s.invoices[reference]; ok {
+		return invoice, true
+	}
+	if number, ok := s.transactions[reference]; ok {
+		return s.invoices[number], true
+	}
+	return nil, false
+}
+
+// expired checks if an unpaid invoice is past its expiry time
+func (s *Server) expired(invoice *irembopay.Invoice) bool {
+	if invoice.ExpiryAt == "" {
+		return false
+	}
+	expiry, err := irembopay.ParseTime(invoice.ExpiryAt)
+	if err != nil {
+		return false
+	}
+	return !s.now().Before(expiry)
+}
+
+// newInvoiceNumber allocates the next invoice number
+func (s *Server) newInvoiceNumber() string {
+	number := fmt.Sprintf("%d", s.nextInvoice)
+	s.nextInvoice++
+	return number
+}
+
+// paymentLink returns the checkout URL of an invoice
+func (s *Server) paymentLink(invoiceNumber string) string {
+	return fmt.Sprintf("%s/checkout/%s", s.server.URL, invoiceNumber)
+}
+
+// queryInt reads an integer query parameter
+func queryInt(query url.Values, name string, defaultValue int) (int, error) {
+	value := query.Get(name)
+	if value == "" {
+		return defaultValue, nil
+	}
+	return strconv.Atoi(value)
+}
+
+// matchQuery checks if the value matches the query parameter, if set
+func matchQuery(query url.Values, name, value string) bool {
+	want := query.Get(name)
+	return want == "" || strings.EqualFold(want, value)
+}
+
+// matchCustomer checks if the customer matches the customer query parameters, if set
+func matchCustomer(query url.Values, customer *irembopay.Customer) bool {
+	email, phoneNumber := query.Get("customerEmail"), query.Get("customerPhoneNumber")
+	if email == "" && phoneNumber == "" {
+		return true
+	}
+	if customer == nil {
+		return false
+	}
+	if email != "" && !strings.EqualFold(email, customer.Email) {
+		return false
+	}
+	if phoneNumber != "" && normalizePhone(phoneNumber) != normalizePhone(customer.PhoneNumber) {
+		return false
+	}
+	return true
+}
+
+// normalizePhone returns the local form of a phone number, or the number unchanged if it is invalid
+func normalizePhone(s string) string {
+	if local, err := phone.Normalize(s); err == nil {
+		return local
+	}
+	return s
+}
+
+// validateInvoiceRequest returns a message describing the first problem of the request
+func validateInvoiceRequest(req *irembopay.InvoiceRequest) string {
+	if req.TransactionID == "" {
+		return "transactionId is required"
+	}
+	if req.PaymentAccountIdentifier == "" {
+		return "paymentAccountIdentifier is required"
+	}
+	if req.ExpiryAt != "" {
+		if _, err := irembopay.ParseTime(req.ExpiryAt); err != nil {
+			return "expiryAt must be an RFC3339 time"
+		}
+	}
+	return validatePaymentItems(req.PaymentItems)
+}
+
+// validatePaymentItems returns a message describing the first invalid payment item
+func validatePaymentItems(items []irembopay.PaymentItem) string {
+	if len(items) == 0 {
+		return "paymentItems must not be empty"
+	}
+	for i, item := range items {
+		if item.Code == "" {
+			return fmt.Sprintf("paymentItems[%d].code is required", i)
+		}
+		if item.Quantity <= 0 {
+			return fmt.Sprintf("paymentItems[%d].quantity must be greater than 0", i)
+		}
+		if item.UnitAmount < 0 {
+			return fmt.Sprintf("paymentItems[%d].unitAmount cannot be negative", i)
+		}
+	}
+	return ""
+}
+
+// totalAmount sums the amounts of the payment items
+func totalAmount(items []irembopay.PaymentItem) float64 {
+	var total float64
+	for _, item := range items {
+		total += float64(item.Quantity) * item.UnitAmount
+	}
+	return total
+}
+
+// currencyOf derives the currency from a payment account identifier such as "TST-RWF"
+func currencyOf(paymentAccountIdentifier string) irembopay.Currency {
+	if i := strings.LastIndex(paymentAccountIdentifier, "-"); i >= 0 {
+		if currency, err := irembopay.ParseCurrency(paymentAccountIdentifier[i+1:]); err == nil {
+			return currency
+		}
+	}
+	return irembopay.RWF
+}
+
+// copyInvoice returns a deep copy of the invoice
+func copyInvoice(invoice *irembopay.Invoice) *irembopay.Invoice {
+	copied := *invoice
+	if invoice.PaymentItems != nil {
+		copied.PaymentItems = append([]irembopay.PaymentItem(nil), invoice.PaymentItems...)
+	}
+	if invoice.ChildInvoices != nil {
+		copied.ChildInvoices = append([]string(nil), invoice.ChildInvoices...)
+	}
+	if invoice.Customer != nil {
+		customer := *invoice.Customer
+		copied.Customer = &customer
+	}
+	return &copied
+}
+
+// encodeResponse encodes data or an error message in the IremboPay response envelope
+func encodeResponse(statusCode int, data interface{}) []byte {
+	var resp map[string]interface{}
+	if statusCode >= 200 && statusCode < 300 {
+		resp = map[string]interface{}{"success": true, "message": "Success", "data": data}
+	} else {
+		resp = map[string]interface{}{"success": false, "message": data}
+	}
+
+	body, err := json.Marshal(resp)
+	if err != nil {
+		panic(fmt.Sprintf("irembopaytest: failed to encode response: %v", err))
+	}
+	return body
+}
+
+func writeData(w http.ResponseWriter, statusCode int, data interface{}) {
+	writeRaw(w, statusCode, encodeResponse(statusCode, data))
+}
+
+func writeError(w http.ResponseWriter, statusCode int, message string) {
+	writeRaw(w, statusCode, encodeResponse(statusCode, message))
+}
+
+func writeRaw(w http.ResponseWriter, statusCode int, body []byte) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	w.Write(body)
+}
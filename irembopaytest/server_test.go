@@ -0,0 +1,149 @@
+package irembopaytest_test
+
+import (
+	"context"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cruso003/irembopay"
+	"github.com/cruso003/irembopay/irembopaytest"
+)
+
+// invoiceRequest returns a valid invoice request for the transaction
+func invoiceRequest(transactionID string) *irembopay.InvoiceRequest {
+	return &irembopay.InvoiceRequest{
+		TransactionID:            transactionID,
+		PaymentAccountIdentifier: "TST-RWF",
+		PaymentItems:             []irembopay.PaymentItem{{Code: "PC-1", Quantity: 2, UnitAmount: 1500}},
+		Description:              "Test invoice",
+	}
+}
+
+func TestServerRoundTrip(t *testing.T) {
+	srv := irembopaytest.NewServer("test-secret-key")
+	defer srv.Close()
+
+	// Freeze the clock so that redeliveries carry the same signature
+	now := time.Now()
+	srv.SetClock(func() time.Time { return now })
+
+	client, err := srv.NewClient(irembopay.WithReplayStore(irembopay.NewMemoryReplayStore(100), time.Hour))
+	if err != nil {
+		t.Fatalf("NewClient returned error: %v", err)
+	}
+	ctx := context.Background()
+
+	// Create an invoice
+	invoice, err := client.Invoice.Create(ctx, invoiceRequest("TST-001"))
+	if err != nil {
+		t.Fatalf("Create returned error: %v", err)
+	}
+	if invoice.InvoiceNumber == "" || invoice.PaymentStatus != irembopay.PaymentStatusNew {
+		t.Fatalf("Create returned invoice %q with status %s, want a NEW invoice", invoice.InvoiceNumber, invoice.PaymentStatus)
+	}
+	if invoice.Amount != 3000 || invoice.Currency != irembopay.RWF {
+		t.Errorf("Create returned amount %v %s, want 3000 RWF", invoice.Amount, invoice.Currency)
+	}
+
+	// Reusing the transaction ID is a conflict
+	if _, err := client.Invoice.Create(ctx, invoiceRequest("TST-001")); !irembopay.IsConflictError(err) {
+		t.Errorf("duplicate Create returned %v, want a conflict", err)
+	}
+
+	// Repeating a request with the same idempotency key replays the response
+	first, err := client.Invoice.CreateWithIdempotency(ctx, invoiceRequest("TST-002"), "key-002")
+	if err != nil {
+		t.Fatalf("CreateWithIdempotency returned error: %v", err)
+	}
+	replayed, err := client.Invoice.CreateWithIdempotency(ctx, invoiceRequest("TST-002"), "key-002")
+	if err != nil {
+		t.Fatalf("repeated CreateWithIdempotency returned error: %v", err)
+	}
+	if replayed.InvoiceNumber != first.InvoiceNumber {
+		t.Errorf("replayed invoice number = %q, want %q", replayed.InvoiceNumber, first.InvoiceNumber)
+	}
+	if got := len(srv.Invoices()); got != 2 {
+		t.Errorf("server has %d invoices, want 2", got)
+	}
+
+	// Pay the first invoice
+	notification, err := srv.MarkPaid(invoice.InvoiceNumber, irembopay.PaymentMethodMTNMomo)
+	if err != nil {
+		t.Fatalf("MarkPaid returned error: %v", err)
+	}
+	paid, err := client.Invoice.Get(ctx, invoice.InvoiceNumber)
+	if err != nil {
+		t.Fatalf("Get returned error: %v", err)
+	}
+	if !paid.IsPaid() || paid.PaymentMethod != irembopay.PaymentMethodMTNMomo {
+		t.Errorf("Get returned status %s paid with %s, want PAID with MTN_MOMO", paid.PaymentStatus, paid.PaymentMethod)
+	}
+
+	// Deliver the notification to a webhook handler
+	var mu sync.Mutex
+	var received []*irembopay.PaymentNotification
+	duplicates := 0
+	handler := client.Payment.WebhookHandler(irembopay.WebhookOptions{
+		OnNotification: func(ctx context.Context, n *irembopay.PaymentNotification) error {
+			mu.Lock()
+			defer mu.Unlock()
+			received = append(received, n)
+			return nil
+		},
+		OnDuplicate: func(ctx context.Context, n *irembopay.PaymentNotification) {
+			mu.Lock()
+			defer mu.Unlock()
+			duplicates++
+		},
+	})
+	webhookSrv := httptest.NewServer(handler)
+	defer webhookSrv.Close()
+
+	if err := srv.SendWebhook(ctx, webhookSrv.URL, notification); err != nil {
+		t.Fatalf("SendWebhook returned error: %v", err)
+	}
+	mu.Lock()
+	if len(received) != 1 {
+		t.Fatalf("handler received %d notifications, want 1", len(received))
+	}
+	got := received[0]
+	mu.Unlock()
+	if got.InvoiceNumber != invoice.InvoiceNumber || got.TransactionID != "TST-001" {
+		t.Errorf("notification is for invoice %q transaction %q, want %q TST-001", got.InvoiceNumber, got.TransactionID, invoice.InvoiceNumber)
+	}
+	if got.PaymentStatus != irembopay.PaymentStatusPaid || got.PaymentMerchantID != irembopaytest.MerchantID {
+		t.Errorf("notification has status %s merchant %q, want PAID %q", got.PaymentStatus, got.PaymentMerchantID, irembopaytest.MerchantID)
+	}
+
+	// A redelivery of the same signed notification is acknowledged as a duplicate
+	if err := srv.SendWebhook(ctx, webhookSrv.URL, notification); err != nil {
+		t.Fatalf("repeated SendWebhook returned error: %v", err)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if len(received) != 1 || duplicates != 1 {
+		t.Errorf("after a repeated delivery: %d notifications and %d duplicates, want 1 and 1", len(received), duplicates)
+	}
+
+	// A paid invoice cannot be paid again
+	if _, err := srv.MarkPaid(invoice.InvoiceNumber, irembopay.PaymentMethodMTNMomo); err == nil {
+		t.Error("MarkPaid of a paid invoice should fail")
+	}
+}
+
+func TestServerRejectsWrongSecretKey(t *testing.T) {
+	srv := irembopaytest.NewServer("test-secret-key")
+	defer srv.Close()
+
+	config, err := irembopay.NewConfig(irembopay.Sandbox, "wrong-key", srv.Options()...)
+	if err != nil {
+		t.Fatalf("NewConfig returned error: %v", err)
+	}
+	client := irembopay.NewIremboPay(config)
+
+	if _, err := client.Invoice.Create(context.Background(), invoiceRequest("TST-001")); !irembopay.IsUnauthorizedError(err) {
+		t.Errorf("Create returned %v, want an unauthorized error", err)
+	}
+}
@@ -0,0 +1,108 @@
+package irembopaytest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/cruso003/irembopay"
+)
+
+// MerchantID is the merchant identifier reported in notifications sent by the server
+const MerchantID = "TST-MERCHANT"
+
+// MarkPaid marks an unpaid invoice as paid with the given payment method
+// (for example irembopay.PaymentMethodMTNMomo) and returns the corresponding notification.
+// Paying a batch invoice also pays its child invoices.
+func (s *Server) MarkPaid(invoiceNumber string, paymentMethod irembopay.PaymentMethod) (*irembopay.PaymentNotification, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	invoice, ok := s.invoices[invoiceNumber]
+	if !ok {
+		return nil, fmt.Errorf("invoice not found: %s", invoiceNumber)
+	}
+	if invoice.PaymentStatus != irembopay.PaymentStatusNew {
+		return nil, fmt.Errorf("invoice %s is not payable (status %s)", invoiceNumber, invoice.PaymentStatus)
+	}
+	if s.expired(invoice) {
+		return nil, fmt.Errorf("invoice %s has expired", invoiceNumber)
+	}
+
+	now := s.now()
+	s.nextReference++
+	reference := fmt.Sprintf("PAY-%08d", s.nextReference)
+
+	pay := func(inv *irembopay.Invoice) {
+		inv.PaymentStatus = irembopay.PaymentStatusPaid
+		inv.PaymentMethod = paymentMethod
+		inv.PaymentReference = reference
+		inv.PaidAt = irembopay.FormatTime(now)
+		inv.UpdatedAt = irembopay.FormatTime(now)
+	}
+	pay(invoice)
+	for _, number := range invoice.ChildInvoices {
+		if child, ok := s.invoices[number]; ok {
+			pay(child)
+		}
+	}
+
+	notification := irembopay.NewNotificationBuilder().
+		ForInvoice(invoice).
+		WithMerchantID(MerchantID).
+		Build()
+	return notification, nil
+}
+
+// Sign returns the irembopay-signature header value for a payload sent at the given time
+func (s *Server) Sign(payload []byte, t time.Time) string {
+	return irembopay.SignWebhook(s.secretKey, string(payload), t)
+}
+
+// SendWebhook posts a signed notification to the given URL.
+// It returns an error if the receiver does not respond with a 2xx status code.
+func (s *Server) SendWebhook(ctx context.Context, url string, notification *irembopay.PaymentNotification) error {
+	payload, err := json.Marshal(notification)
+	if err != nil {
+		return fmt.Errorf("failed to encode notification: %w", err)
+	}
+
+	s.mu.Lock()
+	now := s.now()
+	s.mu.Unlock()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("failed to create webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("irembopay-signature", s.Sign(payload, now))
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send webhook: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("webhook rejected with status code %d: %s", resp.StatusCode, string(body))
+	}
+	return nil
+}
+
+// PayAndNotify marks an invoice as paid and sends the resulting notification to the given URL
+func (s *Server) PayAndNotify(ctx context.Context, invoiceNumber string, paymentMethod irembopay.PaymentMethod, url string) (*irembopay.PaymentNotification, error) {
+	notification, err := s.MarkPaid(invoiceNumber, paymentMethod)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.SendWebhook(ctx, url, notification); err != nil {
+		return notification, err
+	}
+	return notification, nil
+}
@@ -0,0 +1,165 @@
+package irembopay
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// DefaultPageSize is the number of invoices per page when the filter does not set one
+const DefaultPageSize = 20
+
+// InvoiceFilter selects the invoices returned by InvoiceService.List.
+// Zero values are ignored.
+type InvoiceFilter struct {
+	CreatedFrom              time.Time     // Only invoices created at or after this time
+	CreatedTo                time.Time     // Only invoices created before this time
+	PaymentStatus            PaymentStatus // NEW, PAID or CANCELLED
+	PaymentAccountIdentifier string        // Payment account of the invoices
+	CustomerEmail            string        // Email of the customer
+	CustomerPhoneNumber      string        // Phone number of the customer
+	Page                     int           // Page number, starting at 1 (default: 1)
+	PageSize                 int           // Invoices per page (default: DefaultPageSize)
+}
+
+// Validate checks the filter and reports all invalid fields
+func (f *InvoiceFilter) Validate() error {
+	if f == nil {
+		return nil
+	}
+
+	var v ValidationError
+	if f.Page < 0 {
+		v.add("page", "cannot be negative")
+	}
+	if f.PageSize < 0 {
+		v.add("size", "cannot be negative")
+	}
+	if !f.CreatedFrom.IsZero() && !f.CreatedTo.IsZero() && f.CreatedTo.Before(f.CreatedFrom) {
+		v.add("createdTo", "must not be before createdFrom")
+	}
+	if f.PaymentStatus != "" && !f.PaymentStatus.IsValid() {
+		v.add("paymentStatus", "must be NEW, PAID or CANCELLED")
+	}
+
+	return v.err()
+}
+
+// params returns the query parameters of the filter
+func (f *InvoiceFilter) params() map[string]string {
+	params := map[string]string{
+		"page": "1",
+		"size": strconv.Itoa(DefaultPageSize),
+	}
+	if f == nil {
+		return params
+	}
+
+	if f.Page > 0 {
+		params["page"] = strconv.Itoa(f.Page)
+	}
+	if f.PageSize > 0 {
+		params["size"] = strconv.Itoa(f.PageSize)
+	}
+	if !f.CreatedFrom.IsZero() {
+		params["createdFrom"] = FormatTime(f.CreatedFrom)
+	}
+	if !f.CreatedTo.IsZero() {
+		params["createdTo"] = FormatTime(f.CreatedTo)
+	}
+	if f.PaymentStatus != "" {
+		params["paymentStatus"] = string(f.PaymentStatus)
+	}
+	if f.PaymentAccountIdentifier != "" {
+		params["paymentAccountIdentifier"] = f.PaymentAccountIdentifier
+	}
+	if f.CustomerEmail != "" {
+		params["customerEmail"] = f.CustomerEmail
+	}
+	if f.CustomerPhoneNumber != "" {
+		params["customerPhoneNumber"] = f.CustomerPhoneNumber
+	}
+	return params
+}
+
+// InvoicePage is a page of invoices returned by InvoiceService.List
+type InvoicePage struct {
+	Invoices   []Invoice `json:"invoices"`   // Invoices of the page
+	Page       int       `json:"page"`       // Page number, starting at 1
+	PageSize   int       `json:"size"`       // Maximum number of invoices per page
+	TotalItems int       `json:"totalItems"` // Number of invoices matching the filter
+	TotalPages int       `json:"totalPages"` // Number of pages matching the filter
+}
+
+// HasNext checks if there are pages after this one
+func (p *InvoicePage) HasNext() bool {
+	return p.Page < p.TotalPages
+}
+
+// List retrieves a page of invoices matching the filter. A nil filter
+// returns the first page of all invoices.
+func (s *InvoiceService) List(ctx context.Context, filter *InvoiceFilter) (*InvoicePage, error) {
+	if err := validateRequest(s.config, filter); err != nil {
+		return nil, fmt.Errorf("failed to list invoices: %w", err)
+	}
+
+	var page InvoicePage
+	apiReq := Request{
+		Operation: "invoice.list",
+		Method:    http.MethodGet,
+		Path:      "/payments/invoices",
+		Params:    filter.params(),
+	}
+
+	err := s.client.DoRequest(ctx, apiReq, &page)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list invoices: %w", err)
+	}
+
+	return &page, nil
+}
+
+// ListAll returns an iterator over all the invoices matching the filter,
+// starting at the page of the filter and following the next pages as needed.
+// The iteration stops after yielding an error.
+//
+//	for invoice, err := range client.Invoice.ListAll(ctx, filter) {
+//		if err != nil {
+//			return err
+//		}
+//		...
+//	}
+func (s *InvoiceService) ListAll(ctx context.Context, filter *InvoiceFilter) iter.Seq2[*Invoice, error] {
+	return func(yield func(*Invoice, error) bool) {
+		pageFilter := InvoiceFilter{}
+		if filter != nil {
+			pageFilter = *filter
+		}
+		if pageFilter.Page <= 0 {
+			pageFilter.Page = 1
+		}
+
+		for {
+			page, err := s.List(ctx, &pageFilter)
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+
+			for i := range page.Invoices {
+				if !yield(&page.Invoices[i], nil) {
+					return
+				}
+			}
+
+			// Stop on an empty page too, in case the totals are inconsistent
+			if !page.HasNext() || len(page.Invoices) == 0 {
+				return
+			}
+			pageFilter.Page++
+		}
+	}
+}
@@ -0,0 +1,177 @@
+package irembopay
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// RedactedValue replaces the redacted values in log records
+const RedactedValue = "[REDACTED]"
+
+// RedactionPolicy controls which values are hidden from the log records
+// written by the SDK
+type RedactionPolicy struct {
+	Headers []string // Headers whose values are redacted (case-insensitive)
+	Fields  []string // JSON fields and query parameters whose values are redacted (case-insensitive)
+
+	LogHeaders bool // Log the request headers
+	LogBodies  bool // Log the request and response bodies
+}
+
+// DefaultRedactionPolicy returns the default redaction policy: the secret key,
+// webhook signatures, customer emails and phone numbers are redacted, and
+// headers and bodies are not logged
+func DefaultRedactionPolicy() RedactionPolicy {
+	return RedactionPolicy{
+		Headers: []string{"irembopay-secretKey", SignatureHeader, "Authorization"},
+		Fields:  []string{"email", "phoneNumber", "accountIdentifier", "customerEmail", "customerPhoneNumber"},
+	}
+}
+
+// redactsHeader checks if the values of the header are redacted
+func (p *RedactionPolicy) redactsHeader(name string) bool {
+	return containsFold(p.Headers, name)
+}
+
+// redactsField checks if the values of the JSON field or query parameter are redacted
+func (p *RedactionPolicy) redactsField(name string) bool {
+	return containsFold(p.Fields, name)
+}
+
+// header returns the value of a header, redacted if required
+func (p *RedactionPolicy) header(name, value string) string {
+	if value != "" && p.redactsHeader(name) {
+		return RedactedValue
+	}
+	return value
+}
+
+// headersAttr returns the headers as a log attribute group
+func (p *RedactionPolicy) headersAttr(key string, header http.Header) slog.Attr {
+	attrs := make([]any, 0, len(header))
+	for name, values := range header {
+		attrs = append(attrs, slog.String(name, p.header(name, strings.Join(values, ", "))))
+	}
+	return slog.Group(key, attrs...)
+}
+
+// paramsAttr returns the query parameters as a log attribute group
+func (p *RedactionPolicy) paramsAttr(params map[string]string) slog.Attr {
+	attrs := make([]any, 0, len(params))
+	for name, value := range params {
+		if p.redactsField(name) {
+			value = RedactedValue
+		}
+		attrs = append(attrs, slog.String(name, value))
+	}
+	return slog.Group("params", attrs...)
+}
+
+// body returns a JSON body with the redacted fields replaced. Bodies that are
+// not valid JSON are not logged.
+func (p *RedactionPolicy) body(data []byte) string {
+	if len(data) == 0 {
+		return ""
+	}
+
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return "[non-JSON body omitted]"
+	}
+
+	redacted, err := json.Marshal(p.redactValue(value))
+	if err != nil {
+		return "[body omitted]"
+	}
+	return string(redacted)
+}
+
+// redactValue replaces the redacted fields of a decoded JSON value
+func (p *RedactionPolicy) redactValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, field := range v {
+			if p.redactsField(key) {
+				v[key] = RedactedValue
+			} else {
+				v[key] = p.redactValue(field)
+			}
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = p.redactValue(item)
+		}
+	}
+	return value
+}
+
+// containsFold checks if the list contains the name, ignoring case
+func containsFold(list []string, name string) bool {
+	for _, item := range list {
+		if strings.EqualFold(item, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// redaction returns the configured redaction policy, or the default one
+func (c *Config) redaction() *RedactionPolicy {
+	if c.Redaction != nil {
+		return c.Redaction
+	}
+	policy := DefaultRedactionPolicy()
+	return &policy
+}
+
+// logAttempt logs a single attempt of an API request
+func (c *Client) logAttempt(ctx context.Context, req Request, httpReq *http.Request, body []byte, attempt int, latency time.Duration, resp *http.Response, respBody []byte, err error) {
+	logger := c.config.Logger
+	if logger == nil {
+		return
+	}
+
+	policy := c.config.redaction()
+	attrs := []slog.Attr{
+		slog.String("method", req.Method),
+		slog.String("path", req.Path),
+		slog.Int("attempt", attempt),
+		slog.Duration("latency", latency),
+	}
+	if key := httpReq.Header.Get("X-Idempotency-Key"); key != "" {
+		attrs = append(attrs, slog.String("idempotency_key", key))
+	}
+	if len(req.Params) > 0 {
+		attrs = append(attrs, policy.paramsAttr(req.Params))
+	}
+	if policy.LogHeaders {
+		attrs = append(attrs, policy.headersAttr("headers", httpReq.Header))
+	}
+	if policy.LogBodies && len(body) > 0 {
+		attrs = append(attrs, slog.String("request_body", policy.body(body)))
+	}
+
+	level := slog.LevelInfo
+	if resp != nil {
+		attrs = append(attrs, slog.Int("status", resp.StatusCode))
+		if id := requestID(resp.Header); id != "" {
+			attrs = append(attrs, slog.String("request_id", id))
+		}
+		if resp.StatusCode >= 400 {
+			level = slog.LevelWarn
+		}
+		if policy.LogBodies && len(respBody) > 0 {
+			attrs = append(attrs, slog.String("response_body", policy.body(respBody)))
+		}
+	}
+	if err != nil {
+		level = slog.LevelWarn
+		attrs = append(attrs, slog.String("error", err.Error()))
+	}
+
+	logger.LogAttrs(ctx, level, "irembopay request", attrs...)
+}
@@ -0,0 +1,110 @@
+package irembopay_test
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cruso003/irembopay"
+)
+
+// customerInvoiceRequest returns an invoice request with customer details
+func customerInvoiceRequest() *irembopay.InvoiceRequest {
+	req := invoiceRequest()
+	req.Description = "Annual subscription"
+	req.Customer = &irembopay.Customer{Email: "jane@example.com", PhoneNumber: "0781234567", Name: "Jane"}
+	return req
+}
+
+// logRequest creates an invoice with a client logging to a buffer, and returns the log
+func logRequest(t *testing.T, opts ...irembopay.ConfigOption) string {
+	t.Helper()
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusCreated, `{"success":true,"message":"Success","data":{"invoiceNumber":"880419623157",`+
+			`"customer":{"email":"jane@example.com","phoneNumber":"0781234567"},`+
+			`"payments":[{"accountIdentifier":"0781234567"}]}}`)
+	})
+	client := newTestClient(t, handler, append([]irembopay.ConfigOption{irembopay.WithLogger(logger)}, opts...)...)
+
+	if _, err := client.Invoice.Create(context.Background(), customerInvoiceRequest()); err != nil {
+		t.Fatalf("Create returned error: %v", err)
+	}
+	return buf.String()
+}
+
+func TestLoggingRedactsSecrets(t *testing.T) {
+	policy := irembopay.DefaultRedactionPolicy()
+	policy.LogHeaders = true
+	policy.LogBodies = true
+	log := logRequest(t, irembopay.WithRedactionPolicy(policy))
+
+	for _, secret := range []string{"test-secret-key", "jane@example.com", "0781234567"} {
+		if strings.Contains(log, secret) {
+			t.Errorf("log contains %q: %s", secret, log)
+		}
+	}
+	for _, want := range []string{
+		`"Irembopay-Secretkey":"[REDACTED]"`,
+		`\"email\":\"[REDACTED]\"`,
+		`\"phoneNumber\":\"[REDACTED]\"`,
+		`\"accountIdentifier\":\"[REDACTED]\"`,
+		`\"name\":\"Jane\"`,
+	} {
+		if !strings.Contains(log, want) {
+			t.Errorf("log does not contain %s: %s", want, log)
+		}
+	}
+}
+
+func TestLoggingOmitsHeadersAndBodiesByDefault(t *testing.T) {
+	log := logRequest(t)
+
+	if log == "" {
+		t.Fatal("nothing was logged")
+	}
+	for _, omitted := range []string{"test-secret-key", "Irembopay-Secretkey", "request_body", "jane@example.com"} {
+		if strings.Contains(log, omitted) {
+			t.Errorf("log contains %q: %s", omitted, log)
+		}
+	}
+}
+
+func TestWithRedactionPolicyReplacesDefault(t *testing.T) {
+	log := logRequest(t, irembopay.WithRedactionPolicy(irembopay.RedactionPolicy{
+		Fields:    []string{"description"},
+		LogBodies: true,
+	}))
+
+	if !strings.Contains(log, `\"description\":\"[REDACTED]\"`) {
+		t.Errorf("log does not redact the description: %s", log)
+	}
+	if !strings.Contains(log, "jane@example.com") {
+		t.Errorf("log redacts the email although the policy does not list it: %s", log)
+	}
+}
+
+func TestWebhookLoggingRedactsSignature(t *testing.T) {
+	var buf bytes.Buffer
+	client := newWebhookClient(t, irembopay.WithLogger(slog.New(slog.NewJSONHandler(&buf, nil))))
+	handler := client.Payment.WebhookHandler(irembopay.WebhookOptions{
+		OnNotification: func(ctx context.Context, n *irembopay.PaymentNotification) error { return nil },
+	})
+
+	payload := notificationPayload(t)
+	signature := irembopay.SignWebhook(webhookSecret, payload, time.Now())
+	if code := deliver(handler, payload, signature); code != http.StatusOK {
+		t.Fatalf("delivery answered %d, want 200", code)
+	}
+
+	log := buf.String()
+	if strings.Contains(log, signature) || !strings.Contains(log, `"signature":"[REDACTED]"`) {
+		t.Errorf("log does not redact the signature: %s", log)
+	}
+}
@@ -0,0 +1,28 @@
+package irembopay
+
+import (
+	"net/http"
+)
+
+// Middleware wraps an http.RoundTripper to intercept every request sent to the API
+type Middleware func(next http.RoundTripper) http.RoundTripper
+
+// RoundTripperFunc is an adapter to allow the use of ordinary functions as http.RoundTrippers
+type RoundTripperFunc func(*http.Request) (*http.Response, error)
+
+// RoundTrip implements the http.RoundTripper interface
+func (f RoundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// chainMiddleware wraps the transport with the given middleware.
+// The first middleware is the outermost one and sees the request first.
+func chainMiddleware(transport http.RoundTripper, middleware []Middleware) http.RoundTripper {
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	for i := len(middleware) - 1; i >= 0; i-- {
+		transport = middleware[i](transport)
+	}
+	return transport
+}
@@ -28,7 +28,7 @@ type InvoiceRequest struct {
 	ExpiryAt                 string        `json:"expiryAt,omitempty"`       // Time when the invoice will expire
 	Description              string        `json:"description,omitempty"`    // Description of the invoice
 	Customer                 *Customer     `json:"customer,omitempty"`       // Customer information
-	Language                 string        `json:"language,omitempty"`       // Language (FR, EN, RW)
+	Language                 Language      `json:"language,omitempty"`       // Language (FR, EN, RW)
 	IdempotencyKey           string        `json:"-"`                        // Unique key to prevent duplicate requests
 }
 
@@ -40,6 +40,11 @@ type BatchInvoiceRequest struct {
 	IdempotencyKey string   `json:"-"`                     // Unique key to prevent duplicate requests
 }
 
+// CancelInvoiceRequest represents the request to cancel an invoice
+type CancelInvoiceRequest struct {
+	Reason string `json:"reason,omitempty"` // Reason for the cancellation
+}
+
 // UpdateInvoiceRequest represents the request to update an invoice
 type UpdateInvoiceRequest struct {
 	ExpiryAt     string        `json:"expiryAt,omitempty"`     // New expiration date
@@ -48,43 +53,45 @@ type UpdateInvoiceRequest struct {
 
 // Invoice represents an invoice response from IremboPay
 type Invoice struct {
-	Amount                   float64       `json:"amount"`                     // Amount of the invoice
-	InvoiceNumber            string        `json:"invoiceNumber"`              // Identifier of the invoice
-	TransactionID            string        `json:"transactionId"`              // Transaction identifier
-	CreatedAt                string        `json:"createdAt"`                  // Creation date
-	UpdatedAt                string        `json:"updatedAt,omitempty"`        // Last update date
-	ExpiryAt                 string        `json:"expiryAt,omitempty"`         // Expiration date
-	PaidAt                   string        `json:"paidAt,omitempty"`           // Payment date
-	PaymentAccountIdentifier string        `json:"paymentAccountIdentifier"`   // Payment account identifier
-	PaymentItems             []PaymentItem `json:"paymentItems"`               // List of payment items
-	Description              string        `json:"description,omitempty"`      // Description of the invoice
-	Type                     string        `json:"type"`                       // SINGLE or BATCH
-	PaymentStatus            string        `json:"paymentStatus"`              // NEW or PAID
-	PaymentReference         string        `json:"paymentReference,omitempty"` // Reference provided after payment
-	PaymentMethod            string        `json:"paymentMethod,omitempty"`    // MTN_MOMO, AIRTEL_MONEY, etc.
-	Currency                 string        `json:"currency"`                   // RWF, EUR, GBP, USD
-	Customer                 *Customer     `json:"customer,omitempty"`         // Customer information
-	Language                 string        `json:"language,omitempty"`         // Language
-	BatchNumber              string        `json:"batchNumber,omitempty"`      // Batch invoice number
-	ChildInvoices            []string      `json:"childInvoices,omitempty"`    // Invoices in the batch
-	PaymentLinkUrl           string        `json:"paymentLinkUrl"`             // Checkout URL
+	Amount                   float64       `json:"amount"`                       // Amount of the invoice
+	InvoiceNumber            string        `json:"invoiceNumber"`                // Identifier of the invoice
+	TransactionID            string        `json:"transactionId"`                // Transaction identifier
+	CreatedAt                string        `json:"createdAt"`                    // Creation date
+	UpdatedAt                string        `json:"updatedAt,omitempty"`          // Last update date
+	ExpiryAt                 string        `json:"expiryAt,omitempty"`           // Expiration date
+	PaidAt                   string        `json:"paidAt,omitempty"`             // Payment date
+	CancelledAt              string        `json:"cancelledAt,omitempty"`        // Cancellation date
+	CancellationReason       string        `json:"cancellationReason,omitempty"` // Reason given when cancelling
+	PaymentAccountIdentifier string        `json:"paymentAccountIdentifier"`     // Payment account identifier
+	PaymentItems             []PaymentItem `json:"paymentItems"`                 // List of payment items
+	Description              string        `json:"description,omitempty"`        // Description of the invoice
+	Type                     InvoiceType   `json:"type"`                         // SINGLE or BATCH
+	PaymentStatus            PaymentStatus `json:"paymentStatus"`                // NEW, PAID or CANCELLED
+	PaymentReference         string        `json:"paymentReference,omitempty"`   // Reference provided after payment
+	PaymentMethod            PaymentMethod `json:"paymentMethod,omitempty"`      // MTN_MOMO, AIRTEL_MONEY, etc.
+	Currency                 Currency      `json:"currency"`                     // RWF, EUR, GBP, USD
+	Customer                 *Customer     `json:"customer,omitempty"`           // Customer information
+	Language                 Language      `json:"language,omitempty"`           // Language
+	BatchNumber              string        `json:"batchNumber,omitempty"`        // Batch invoice number
+	ChildInvoices            []string      `json:"childInvoices,omitempty"`      // Invoices in the batch
+	PaymentLinkUrl           string        `json:"paymentLinkUrl"`               // Checkout URL
 }
 
 // MomoPaymentRequest represents a request to initiate a mobile money payment
 type MomoPaymentRequest struct {
-	AccountIdentifier    string `json:"accountIdentifier"`              // Phone number
-	PaymentProvider      string `json:"paymentProvider"`                // MTN or AIRTEL
-	InvoiceNumber        string `json:"invoiceNumber"`                  // Invoice number
-	TransactionReference string `json:"transactionReference,omitempty"` // Optional reference
+	AccountIdentifier    string          `json:"accountIdentifier"`              // Phone number
+	PaymentProvider      PaymentProvider `json:"paymentProvider"`                // MTN or AIRTEL
+	InvoiceNumber        string          `json:"invoiceNumber"`                  // Invoice number
+	TransactionReference string          `json:"transactionReference,omitempty"` // Optional reference
 }
 
 // MomoPaymentResponse represents the response to a mobile money payment request
 type MomoPaymentResponse struct {
-	AccountIdentifier string  `json:"accountIdentifier"` // Phone number
-	PaymentProvider   string  `json:"paymentProvider"`   // MTN or AIRTEL
-	InvoiceNumber     string  `json:"invoiceNumber"`     // Invoice number
-	Amount            float64 `json:"amount"`            // Amount
-	ReferenceID       string  `json:"referenceId"`       // IremboPay reference
+	AccountIdentifier string          `json:"accountIdentifier"` // Phone number
+	PaymentProvider   PaymentProvider `json:"paymentProvider"`   // MTN or AIRTEL
+	InvoiceNumber     string          `json:"invoiceNumber"`     // Invoice number
+	Amount            float64         `json:"amount"`            // Amount
+	ReferenceID       string          `json:"referenceId"`       // IremboPay reference
 }
 
 // Response is the standard API response from IremboPay
@@ -96,16 +103,36 @@ type Response struct {
 
 // PaymentNotification represents a payment notification from IremboPay
 type PaymentNotification struct {
-	InvoiceNumber     string  `json:"invoiceNumber"`     // Invoice number
-	TransactionID     string  `json:"transactionId"`     // Transaction ID
-	PaymentStatus     string  `json:"paymentStatus"`     // Payment status
-	PaymentReference  string  `json:"paymentReference"`  // Payment reference
-	Amount            float64 `json:"amount"`            // Amount
-	Currency          string  `json:"currency"`          // Currency
-	PaymentMethod     string  `json:"paymentMethod"`     // Payment method
-	PaidAt            string  `json:"paidAt"`            // Payment date
-	PaymentAccountID  string  `json:"paymentAccountId"`  // Payment account ID
-	PaymentMerchantID string  `json:"paymentMerchantId"` // Merchant ID
+	InvoiceNumber     string        `json:"invoiceNumber"`     // Invoice number
+	TransactionID     string        `json:"transactionId"`     // Transaction ID
+	PaymentStatus     PaymentStatus `json:"paymentStatus"`     // Payment status
+	PaymentReference  string        `json:"paymentReference"`  // Payment reference
+	Amount            float64       `json:"amount"`            // Amount
+	Currency          Currency      `json:"currency"`          // Currency
+	PaymentMethod     PaymentMethod `json:"paymentMethod"`     // Payment method
+	PaidAt            string        `json:"paidAt"`            // Payment date
+	PaymentAccountID  string        `json:"paymentAccountId"`  // Payment account ID
+	PaymentMerchantID string        `json:"paymentMerchantId"` // Merchant ID
+}
+
+// IsPaid checks if the invoice has been paid
+func (i *Invoice) IsPaid() bool {
+	return i.PaymentStatus == PaymentStatusPaid
+}
+
+// IsCancelled checks if the invoice has been cancelled
+func (i *Invoice) IsCancelled() bool {
+	return i.PaymentStatus == PaymentStatusCancelled
+}
+
+// IsBatch checks if the invoice is a batch invoice
+func (i *Invoice) IsBatch() bool {
+	return i.Type == InvoiceTypeBatch
+}
+
+// IsPaid checks if the notification reports a successful payment
+func (n *PaymentNotification) IsPaid() bool {
+	return n.PaymentStatus == PaymentStatusPaid
 }
 
 // FormatTime formats a time.Time for IremboPay API (RFC3339 format)
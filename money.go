@@ -0,0 +1,240 @@
+package irembopay
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// decimalPattern matches the plain decimal amounts accepted by ParseMoney
+var decimalPattern = regexp.MustCompile(`^-?[0-9]+(\.[0-9]+)?$`)
+
+// MinorUnits returns the number of decimal places used by the currency.
+// Unknown currencies use two decimal places.
+func (c Currency) MinorUnits() int {
+	switch c {
+	case RWF:
+		return 0
+	default:
+		return 2
+	}
+}
+
+// Money is an exact amount of money expressed in the minor units of its currency
+// (for example cents for USD, francs for RWF)
+type Money struct {
+	Amount   int64    // Amount in minor units
+	Currency Currency // Currency of the amount
+}
+
+// NewMoney creates an amount of money from minor units
+func NewMoney(amount int64, currency Currency) Money {
+	return Money{Amount: amount, Currency: currency}
+}
+
+// ParseMoney parses a decimal amount in major units (for example "12.50").
+// Fractions and exponents such as "1/2" or "1e2" are rejected.
+func ParseMoney(s string, currency Currency) (Money, error) {
+	trimmed := strings.TrimSpace(s)
+	if !decimalPattern.MatchString(trimmed) {
+		return Money{}, fmt.Errorf("invalid amount: %q", s)
+	}
+	r, ok := new(big.Rat).SetString(trimmed)
+	if !ok {
+		return Money{}, fmt.Errorf("invalid amount: %q", s)
+	}
+
+	scale := new(big.Rat).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(currency.MinorUnits())), nil))
+	r.Mul(r, scale)
+	if !r.IsInt() {
+		return Money{}, fmt.Errorf("amount %q has more than %d decimal places for %s", s, currency.MinorUnits(), currency)
+	}
+	if !r.Num().IsInt64() {
+		return Money{}, fmt.Errorf("amount %q is out of range", s)
+	}
+
+	return Money{Amount: r.Num().Int64(), Currency: currency}, nil
+}
+
+// maxExactFloat is the largest integer up to which float64 values are exact
+const maxExactFloat = 1 << 53
+
+// MoneyFromFloat converts an amount in major units, as reported by the API, to Money.
+// The amount is never rounded: it returns an error if the shortest decimal form
+// of the value has more decimal places than the currency uses (for example
+// 2.675 USD or 1499.5 RWF), or if the value is too large to be exact.
+func MoneyFromFloat(amount float64, currency Currency) (Money, error) {
+	if math.IsNaN(amount) || math.IsInf(amount, 0) {
+		return Money{}, fmt.Errorf("invalid amount: %v", amount)
+	}
+	if math.Abs(amount)*math.Pow10(currency.MinorUnits()) > maxExactFloat {
+		return Money{}, fmt.Errorf("amount %v is too large to be exact", amount)
+	}
+
+	// The shortest form is the decimal number the value was decoded from
+	return ParseMoney(strconv.FormatFloat(amount, 'f', -1, 64), currency)
+}
+
+// Float64 returns the amount in major units as used by the float fields of the models
+func (m Money) Float64() float64 {
+	f, _ := strconv.ParseFloat(m.Decimal(), 64)
+	return f
+}
+
+// Decimal returns the amount in major units as an exact decimal string (for example "12.50")
+func (m Money) Decimal() string {
+	units := m.Currency.MinorUnits()
+	if units == 0 {
+		return strconv.FormatInt(m.Amount, 10)
+	}
+
+	sign := ""
+	abs := new(big.Int).SetInt64(m.Amount)
+	if abs.Sign() < 0 {
+		sign = "-"
+		abs.Neg(abs)
+	}
+
+	digits := abs.String()
+	if len(digits) <= units {
+		digits = strings.Repeat("0", units-len(digits)+1) + digits
+	}
+	return sign + digits[:len(digits)-units] + "." + digits[len(digits)-units:]
+}
+
+// String formats the amount with its currency (for example "12.50 USD")
+func (m Money) String() string {
+	if m.Currency == "" {
+		return m.Decimal()
+	}
+	return m.Decimal() + " " + string(m.Currency)
+}
+
+// IsZero checks if the amount is zero
+func (m Money) IsZero() bool {
+	return m.Amount == 0
+}
+
+// IsNegative checks if the amount is below zero
+func (m Money) IsNegative() bool {
+	return m.Amount < 0
+}
+
+// Add returns the sum of two amounts in the same currency
+func (m Money) Add(other Money) (Money, error) {
+	if err := m.checkCurrency(other); err != nil {
+		return Money{}, err
+	}
+	sum := m.Amount + other.Amount
+	if (other.Amount > 0 && sum < m.Amount) || (other.Amount < 0 && sum > m.Amount) {
+		return Money{}, fmt.Errorf("amount overflow")
+	}
+	return Money{Amount: sum, Currency: m.Currency}, nil
+}
+
+// Sub returns the difference of two amounts in the same currency
+func (m Money) Sub(other Money) (Money, error) {
+	if other.Amount == math.MinInt64 {
+		return Money{}, fmt.Errorf("amount overflow")
+	}
+	return m.Add(Money{Amount: -other.Amount, Currency: other.Currency})
+}
+
+// Mul returns the amount multiplied by a quantity
+func (m Money) Mul(quantity int64) (Money, error) {
+	product := new(big.Int).Mul(big.NewInt(m.Amount), big.NewInt(quantity))
+	if !product.IsInt64() {
+		return Money{}, fmt.Errorf("amount overflow")
+	}
+	return Money{Amount: product.Int64(), Currency: m.Currency}, nil
+}
+
+// Cmp compares two amounts in the same currency and returns -1, 0 or +1
+func (m Money) Cmp(other Money) (int, error) {
+	if err := m.checkCurrency(other); err != nil {
+		return 0, err
+	}
+	switch {
+	case m.Amount < other.Amount:
+		return -1, nil
+	case m.Amount > other.Amount:
+		return 1, nil
+	default:
+		return 0, nil
+	}
+}
+
+// Equal checks if two amounts have the same value and currency
+func (m Money) Equal(other Money) bool {
+	return m.Amount == other.Amount && m.Currency == other.Currency
+}
+
+// MarshalJSON encodes the amount as a JSON number in major units
+func (m Money) MarshalJSON() ([]byte, error) {
+	return []byte(m.Decimal()), nil
+}
+
+// UnmarshalJSON decodes a JSON number in major units.
+// The API reports amounts without their currency, so Currency must be set
+// before decoding; it defaults to two decimal places otherwise. A JSON null
+// leaves the amount unchanged.
+func (m *Money) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	parsed, err := ParseMoney(string(data), m.Currency)
+	if err != nil {
+		return err
+	}
+	*m = parsed
+	return nil
+}
+
+// checkCurrency returns an error if the amounts use different currencies
+func (m Money) checkCurrency(other Money) error {
+	if m.Currency != other.Currency {
+		return fmt.Errorf("currency mismatch: %s and %s", m.Currency, other.Currency)
+	}
+	return nil
+}
+
+// NewPaymentItem creates a payment item priced with an exact unit amount
+func NewPaymentItem(code string, quantity int, unitAmount Money) PaymentItem {
+	return PaymentItem{
+		Code:       code,
+		Quantity:   quantity,
+		UnitAmount: unitAmount.Float64(),
+	}
+}
+
+// UnitPrice returns the unit amount of the item in the given currency
+func (p PaymentItem) UnitPrice(currency Currency) (Money, error) {
+	return MoneyFromFloat(p.UnitAmount, currency)
+}
+
+// Total returns the unit amount multiplied by the quantity of the item
+func (p PaymentItem) Total(currency Currency) (Money, error) {
+	unitPrice, err := p.UnitPrice(currency)
+	if err != nil {
+		return Money{}, err
+	}
+	return unitPrice.Mul(int64(p.Quantity))
+}
+
+// AmountMoney returns the amount of the invoice in its currency
+func (i *Invoice) AmountMoney() (Money, error) {
+	return MoneyFromFloat(i.Amount, i.Currency)
+}
+
+// AmountMoney returns the amount of the payment in the currency of its invoice
+func (r *MomoPaymentResponse) AmountMoney(currency Currency) (Money, error) {
+	return MoneyFromFloat(r.Amount, currency)
+}
+
+// AmountMoney returns the amount of the notification in its currency
+func (n *PaymentNotification) AmountMoney() (Money, error) {
+	return MoneyFromFloat(n.Amount, n.Currency)
+}
@@ -0,0 +1,232 @@
+package irembopay_test
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+
+	"github.com/cruso003/irembopay"
+)
+
+func TestParseMoney(t *testing.T) {
+	tests := []struct {
+		input    string
+		currency irembopay.Currency
+		want     int64
+		wantErr  bool
+	}{
+		{input: "12.50", currency: irembopay.USD, want: 1250},
+		{input: "12.5", currency: irembopay.USD, want: 1250},
+		{input: "12", currency: irembopay.USD, want: 1200},
+		{input: " 0.01 ", currency: irembopay.EUR, want: 1},
+		{input: "-3.20", currency: irembopay.USD, want: -320},
+		{input: "1500", currency: irembopay.RWF, want: 1500},
+		{input: "92233720368547758.07", currency: irembopay.USD, want: math.MaxInt64},
+		{input: "1500.5", currency: irembopay.RWF, wantErr: true},
+		{input: "0.001", currency: irembopay.USD, wantErr: true},
+		{input: "92233720368547758.08", currency: irembopay.USD, wantErr: true},
+		{input: "1/2", currency: irembopay.USD, wantErr: true},
+		{input: "1e2", currency: irembopay.USD, wantErr: true},
+		{input: "+1", currency: irembopay.USD, wantErr: true},
+		{input: ".5", currency: irembopay.USD, wantErr: true},
+		{input: "5.", currency: irembopay.USD, wantErr: true},
+		{input: "", currency: irembopay.USD, wantErr: true},
+		{input: "abc", currency: irembopay.USD, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := irembopay.ParseMoney(tt.input, tt.currency)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("ParseMoney(%q, %s) = %v, want an error", tt.input, tt.currency, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseMoney(%q, %s) returned error: %v", tt.input, tt.currency, err)
+			continue
+		}
+		if want := irembopay.NewMoney(tt.want, tt.currency); !got.Equal(want) {
+			t.Errorf("ParseMoney(%q, %s) = %v, want %v", tt.input, tt.currency, got, want)
+		}
+	}
+}
+
+func TestMoneyFromFloat(t *testing.T) {
+	tenth, fifth := 0.1, 0.2
+
+	tests := []struct {
+		amount   float64
+		currency irembopay.Currency
+		want     int64
+		wantErr  bool
+	}{
+		{amount: 19.99, currency: irembopay.USD, want: 1999},
+		{amount: 0.3, currency: irembopay.USD, want: 30},
+		{amount: -12.5, currency: irembopay.EUR, want: -1250},
+		{amount: 1500, currency: irembopay.RWF, want: 1500},
+		{amount: 12345678901.23, currency: irembopay.USD, want: 1234567890123},
+
+		// Amounts that would have to be rounded
+		{amount: 2.675, currency: irembopay.USD, wantErr: true},
+		{amount: 1499.5, currency: irembopay.RWF, wantErr: true},
+		{amount: tenth + fifth, currency: irembopay.USD, wantErr: true},
+		{amount: 1e17, currency: irembopay.RWF, wantErr: true},
+		{amount: math.NaN(), currency: irembopay.USD, wantErr: true},
+		{amount: math.Inf(1), currency: irembopay.USD, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := irembopay.MoneyFromFloat(tt.amount, tt.currency)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("MoneyFromFloat(%v, %s) = %v, want an error", tt.amount, tt.currency, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("MoneyFromFloat(%v, %s) returned error: %v", tt.amount, tt.currency, err)
+			continue
+		}
+		if got.Amount != tt.want {
+			t.Errorf("MoneyFromFloat(%v, %s) = %d minor units, want %d", tt.amount, tt.currency, got.Amount, tt.want)
+		}
+	}
+}
+
+func TestMoneyFormatting(t *testing.T) {
+	tests := []struct {
+		money   irembopay.Money
+		decimal string
+		str     string
+	}{
+		{money: irembopay.NewMoney(1250, irembopay.USD), decimal: "12.50", str: "12.50 USD"},
+		{money: irembopay.NewMoney(5, irembopay.EUR), decimal: "0.05", str: "0.05 EUR"},
+		{money: irembopay.NewMoney(-5, irembopay.USD), decimal: "-0.05", str: "-0.05 USD"},
+		{money: irembopay.NewMoney(0, irembopay.USD), decimal: "0.00", str: "0.00 USD"},
+		{money: irembopay.NewMoney(1500, irembopay.RWF), decimal: "1500", str: "1500 RWF"},
+		{money: irembopay.NewMoney(math.MinInt64, irembopay.USD), decimal: "-92233720368547758.08", str: "-92233720368547758.08 USD"},
+		{money: irembopay.NewMoney(1250, ""), decimal: "12.50", str: "12.50"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.money.Decimal(); got != tt.decimal {
+			t.Errorf("Decimal() of %d %s = %q, want %q", tt.money.Amount, tt.money.Currency, got, tt.decimal)
+		}
+		if got := tt.money.String(); got != tt.str {
+			t.Errorf("String() of %d %s = %q, want %q", tt.money.Amount, tt.money.Currency, got, tt.str)
+		}
+	}
+}
+
+func TestMoneyArithmetic(t *testing.T) {
+	usd := func(amount int64) irembopay.Money { return irembopay.NewMoney(amount, irembopay.USD) }
+
+	tests := []struct {
+		name    string
+		op      func() (irembopay.Money, error)
+		want    irembopay.Money
+		wantErr bool
+	}{
+		{name: "add", op: func() (irembopay.Money, error) { return usd(1250).Add(usd(75)) }, want: usd(1325)},
+		{name: "add negative", op: func() (irembopay.Money, error) { return usd(100).Add(usd(-250)) }, want: usd(-150)},
+		{name: "add overflow", op: func() (irembopay.Money, error) { return usd(math.MaxInt64).Add(usd(1)) }, wantErr: true},
+		{name: "add underflow", op: func() (irembopay.Money, error) { return usd(math.MinInt64).Add(usd(-1)) }, wantErr: true},
+		{name: "add currency mismatch", op: func() (irembopay.Money, error) {
+			return usd(100).Add(irembopay.NewMoney(100, irembopay.RWF))
+		}, wantErr: true},
+		{name: "sub", op: func() (irembopay.Money, error) { return usd(1000).Sub(usd(1)) }, want: usd(999)},
+		{name: "sub min int", op: func() (irembopay.Money, error) { return usd(0).Sub(usd(math.MinInt64)) }, wantErr: true},
+		{name: "sub overflow", op: func() (irembopay.Money, error) { return usd(math.MinInt64).Sub(usd(1)) }, wantErr: true},
+		{name: "mul", op: func() (irembopay.Money, error) { return usd(1999).Mul(3) }, want: usd(5997)},
+		{name: "mul negative", op: func() (irembopay.Money, error) { return usd(1999).Mul(-2) }, want: usd(-3998)},
+		{name: "mul overflow", op: func() (irembopay.Money, error) { return usd(math.MaxInt64 / 2).Mul(3) }, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.op()
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("got %v, want an error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMoneyCmp(t *testing.T) {
+	a := irembopay.NewMoney(100, irembopay.USD)
+	b := irembopay.NewMoney(200, irembopay.USD)
+
+	if got, err := a.Cmp(b); err != nil || got != -1 {
+		t.Errorf("Cmp(100, 200) = %d, %v, want -1", got, err)
+	}
+	if got, err := b.Cmp(a); err != nil || got != 1 {
+		t.Errorf("Cmp(200, 100) = %d, %v, want 1", got, err)
+	}
+	if got, err := a.Cmp(a); err != nil || got != 0 {
+		t.Errorf("Cmp(100, 100) = %d, %v, want 0", got, err)
+	}
+	if _, err := a.Cmp(irembopay.NewMoney(100, irembopay.RWF)); err == nil {
+		t.Error("Cmp with a different currency should fail")
+	}
+}
+
+func TestMoneyJSON(t *testing.T) {
+	data, err := json.Marshal(struct {
+		Amount irembopay.Money `json:"amount"`
+	}{irembopay.NewMoney(1250, irembopay.USD)})
+	if err != nil {
+		t.Fatalf("Marshal returned error: %v", err)
+	}
+	if got, want := string(data), `{"amount":12.50}`; got != want {
+		t.Errorf("Marshal = %s, want %s", got, want)
+	}
+
+	decoded := struct {
+		Amount irembopay.Money `json:"amount"`
+	}{Amount: irembopay.Money{Currency: irembopay.RWF}}
+	if err := json.Unmarshal([]byte(`{"amount":1500}`), &decoded); err != nil {
+		t.Fatalf("Unmarshal returned error: %v", err)
+	}
+	if want := irembopay.NewMoney(1500, irembopay.RWF); !decoded.Amount.Equal(want) {
+		t.Errorf("Unmarshal = %v, want %v", decoded.Amount, want)
+	}
+
+	// null leaves the amount unchanged
+	if err := json.Unmarshal([]byte(`{"amount":null}`), &decoded); err != nil {
+		t.Fatalf("Unmarshal of null returned error: %v", err)
+	}
+	if want := irembopay.NewMoney(1500, irembopay.RWF); !decoded.Amount.Equal(want) {
+		t.Errorf("Unmarshal of null = %v, want %v", decoded.Amount, want)
+	}
+
+	if err := json.Unmarshal([]byte(`{"amount":1e2}`), &decoded); err == nil {
+		t.Error("Unmarshal of an exponent should fail")
+	}
+}
+
+func TestInvoiceAmountMoney(t *testing.T) {
+	var invoice irembopay.Invoice
+	if err := json.Unmarshal([]byte(`{"amount":19.99,"currency":"USD"}`), &invoice); err != nil {
+		t.Fatalf("Unmarshal returned error: %v", err)
+	}
+	if got, err := invoice.AmountMoney(); err != nil || !got.Equal(irembopay.NewMoney(1999, irembopay.USD)) {
+		t.Errorf("AmountMoney() = %v, %v, want 19.99 USD", got, err)
+	}
+
+	if err := json.Unmarshal([]byte(`{"amount":1499.5,"currency":"RWF"}`), &invoice); err != nil {
+		t.Fatalf("Unmarshal returned error: %v", err)
+	}
+	if got, err := invoice.AmountMoney(); err == nil {
+		t.Errorf("AmountMoney() of 1499.5 RWF = %v, want an error", got)
+	}
+}
@@ -0,0 +1,138 @@
+// Package otelirembopay adapts OpenTelemetry tracer and meter providers to the
+// instrumentation interfaces of the IremboPay SDK:
+//
+//	client, err := irembopay.NewSandboxClient(secretKey,
+//		irembopay.WithTracer(otelirembopay.NewTracer(otel.GetTracerProvider())),
+//		irembopay.WithMeter(otelirembopay.NewMeter(otel.GetMeterProvider())),
+//	)
+package otelirembopay
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cruso003/irembopay"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ScopeName is the instrumentation scope of the tracers and meters
+const ScopeName = "github.com/cruso003/irembopay"
+
+// NewTracer returns a tracer creating OpenTelemetry spans.
+// A nil provider uses the global tracer provider.
+func NewTracer(provider trace.TracerProvider) irembopay.Tracer {
+	if provider == nil {
+		provider = otel.GetTracerProvider()
+	}
+	return &tracer{tracer: provider.Tracer(ScopeName)}
+}
+
+// tracer implements irembopay.Tracer
+type tracer struct {
+	tracer trace.Tracer
+}
+
+// Start implements irembopay.Tracer
+func (t *tracer) Start(ctx context.Context, name string, attrs ...irembopay.Attribute) (context.Context, irembopay.Span) {
+	ctx, s := t.tracer.Start(ctx, name, trace.WithAttributes(convert(attrs)...))
+	return ctx, &span{span: s}
+}
+
+// span implements irembopay.Span
+type span struct {
+	span trace.Span
+}
+
+// SetAttributes implements irembopay.Span
+func (s *span) SetAttributes(attrs ...irembopay.Attribute) {
+	s.span.SetAttributes(convert(attrs)...)
+}
+
+// RecordError implements irembopay.Span
+func (s *span) RecordError(err error) {
+	s.span.RecordError(err)
+	s.span.SetStatus(codes.Error, err.Error())
+}
+
+// End implements irembopay.Span
+func (s *span) End() {
+	s.span.End()
+}
+
+// NewMeter returns a meter recording OpenTelemetry metrics.
+// A nil provider uses the global meter provider.
+func NewMeter(provider metric.MeterProvider) irembopay.Meter {
+	if provider == nil {
+		provider = otel.GetMeterProvider()
+	}
+	return &meter{meter: provider.Meter(ScopeName)}
+}
+
+// meter implements irembopay.Meter
+type meter struct {
+	meter metric.Meter
+}
+
+// Counter implements irembopay.Meter
+func (m *meter) Counter(name, description string) irembopay.Counter {
+	c, err := m.meter.Int64Counter(name, metric.WithDescription(description))
+	if err != nil {
+		otel.Handle(err)
+	}
+	return &counter{counter: c}
+}
+
+// Histogram implements irembopay.Meter
+func (m *meter) Histogram(name, description, unit string) irembopay.Histogram {
+	h, err := m.meter.Float64Histogram(name, metric.WithDescription(description), metric.WithUnit(unit))
+	if err != nil {
+		otel.Handle(err)
+	}
+	return &histogram{histogram: h}
+}
+
+// counter implements irembopay.Counter
+type counter struct {
+	counter metric.Int64Counter
+}
+
+// Add implements irembopay.Counter
+func (c *counter) Add(ctx context.Context, value int64, attrs ...irembopay.Attribute) {
+	c.counter.Add(ctx, value, metric.WithAttributes(convert(attrs)...))
+}
+
+// histogram implements irembopay.Histogram
+type histogram struct {
+	histogram metric.Float64Histogram
+}
+
+// Record implements irembopay.Histogram
+func (h *histogram) Record(ctx context.Context, value float64, attrs ...irembopay.Attribute) {
+	h.histogram.Record(ctx, value, metric.WithAttributes(convert(attrs)...))
+}
+
+// convert converts SDK attributes to OpenTelemetry attributes
+func convert(attrs []irembopay.Attribute) []attribute.KeyValue {
+	kvs := make([]attribute.KeyValue, 0, len(attrs))
+	for _, attr := range attrs {
+		switch v := attr.Value.(type) {
+		case string:
+			kvs = append(kvs, attribute.String(attr.Key, v))
+		case int:
+			kvs = append(kvs, attribute.Int(attr.Key, v))
+		case int64:
+			kvs = append(kvs, attribute.Int64(attr.Key, v))
+		case float64:
+			kvs = append(kvs, attribute.Float64(attr.Key, v))
+		case bool:
+			kvs = append(kvs, attribute.Bool(attr.Key, v))
+		default:
+			kvs = append(kvs, attribute.String(attr.Key, fmt.Sprint(v)))
+		}
+	}
+	return kvs
+}
@@ -0,0 +1,268 @@
+package otelirembopay_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/cruso003/irembopay"
+	"github.com/cruso003/irembopay/irembopaytest"
+	"github.com/cruso003/irembopay/otelirembopay"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	metricnoop "go.opentelemetry.io/otel/metric/noop"
+	"go.opentelemetry.io/otel/trace"
+	tracenoop "go.opentelemetry.io/otel/trace/noop"
+)
+
+// recordedSpan is a span recorded by recordingTracer
+type recordedSpan struct {
+	tracenoop.Span
+	name    string
+	attrs   map[attribute.Key]attribute.Value
+	errs    []error
+	status  codes.Code
+	message string
+	ended   bool
+}
+
+func (s *recordedSpan) SetAttributes(kvs ...attribute.KeyValue) {
+	for _, kv := range kvs {
+		s.attrs[kv.Key] = kv.Value
+	}
+}
+
+func (s *recordedSpan) RecordError(err error, opts ...trace.EventOption) {
+	s.errs = append(s.errs, err)
+}
+
+func (s *recordedSpan) SetStatus(code codes.Code, description string) {
+	s.status, s.message = code, description
+}
+
+func (s *recordedSpan) End(opts ...trace.SpanEndOption) {
+	s.ended = true
+}
+
+// tracerProvider provides a recordingTracer
+type tracerProvider struct {
+	tracenoop.TracerProvider
+	tracer *recordingTracer
+	scope  string
+}
+
+func (p *tracerProvider) Tracer(name string, opts ...trace.TracerOption) trace.Tracer {
+	p.scope = name
+	return p.tracer
+}
+
+// recordingTracer is a tracer recording the spans started
+type recordingTracer struct {
+	tracenoop.Tracer
+	mu    sync.Mutex
+	spans []*recordedSpan
+}
+
+func (t *recordingTracer) Start(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	s := &recordedSpan{name: name, attrs: make(map[attribute.Key]attribute.Value)}
+	config := trace.NewSpanStartConfig(opts...)
+	s.SetAttributes(config.Attributes()...)
+	t.spans = append(t.spans, s)
+	return ctx, s
+}
+
+// measurement is a value recorded by a recordingMeter instrument
+type measurement struct {
+	name  string
+	value float64
+	attrs attribute.Set
+}
+
+// meterProvider provides a recordingMeter
+type meterProvider struct {
+	metricnoop.MeterProvider
+	meter *recordingMeter
+	scope string
+}
+
+func (p *meterProvider) Meter(name string, opts ...metric.MeterOption) metric.Meter {
+	p.scope = name
+	return p.meter
+}
+
+// recordingMeter is a meter recording the measurements of its instruments
+type recordingMeter struct {
+	metricnoop.Meter
+	mu           sync.Mutex
+	descriptions map[string]string
+	measurements []measurement
+}
+
+func (m *recordingMeter) Int64Counter(name string, opts ...metric.Int64CounterOption) (metric.Int64Counter, error) {
+	m.describe(name, metric.NewInt64CounterConfig(opts...).Description())
+	return &recordingCounter{meter: m, name: name}, nil
+}
+
+func (m *recordingMeter) Float64Histogram(name string, opts ...metric.Float64HistogramOption) (metric.Float64Histogram, error) {
+	m.describe(name, metric.NewFloat64HistogramConfig(opts...).Description())
+	return &recordingHistogram{meter: m, name: name}, nil
+}
+
+func (m *recordingMeter) describe(name, description string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.descriptions == nil {
+		m.descriptions = make(map[string]string)
+	}
+	m.descriptions[name] = description
+}
+
+func (m *recordingMeter) record(name string, value float64, attrs attribute.Set) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.measurements = append(m.measurements, measurement{name: name, value: value, attrs: attrs})
+}
+
+type recordingCounter struct {
+	metricnoop.Int64Counter
+	meter *recordingMeter
+	name  string
+}
+
+func (c *recordingCounter) Add(ctx context.Context, value int64, opts ...metric.AddOption) {
+	c.meter.record(c.name, float64(value), metric.NewAddConfig(opts).Attributes())
+}
+
+type recordingHistogram struct {
+	metricnoop.Float64Histogram
+	meter *recordingMeter
+	name  string
+}
+
+func (h *recordingHistogram) Record(ctx context.Context, value float64, opts ...metric.RecordOption) {
+	h.meter.record(h.name, value, metric.NewRecordConfig(opts).Attributes())
+}
+
+// stringer is an attribute value of an unsupported type
+type stringer struct{}
+
+func (stringer) String() string { return "stringer" }
+
+func TestTracerConvertsAttributes(t *testing.T) {
+	provider := &tracerProvider{tracer: &recordingTracer{}}
+	tracer := otelirembopay.NewTracer(provider)
+	if provider.scope != otelirembopay.ScopeName {
+		t.Errorf("tracer scope = %q, want %q", provider.scope, otelirembopay.ScopeName)
+	}
+
+	_, span := tracer.Start(context.Background(), "test",
+		irembopay.Attribute{Key: "string", Value: "value"},
+		irembopay.Attribute{Key: "int", Value: 42},
+		irembopay.Attribute{Key: "int64", Value: int64(1) << 40},
+		irembopay.Attribute{Key: "float64", Value: 1.5},
+		irembopay.Attribute{Key: "bool", Value: true},
+	)
+	span.SetAttributes(irembopay.Attribute{Key: "other", Value: stringer{}})
+	span.RecordError(errors.New("failed"))
+	span.End()
+
+	spans := provider.tracer.spans
+	if len(spans) != 1 {
+		t.Fatalf("recorded %d spans, want 1", len(spans))
+	}
+	got := spans[0]
+	want := map[attribute.Key]attribute.Value{
+		"string":  attribute.StringValue("value"),
+		"int":     attribute.IntValue(42),
+		"int64":   attribute.Int64Value(1 << 40),
+		"float64": attribute.Float64Value(1.5),
+		"bool":    attribute.BoolValue(true),
+		"other":   attribute.StringValue("stringer"),
+	}
+	for key, value := range want {
+		if got.attrs[key] != value {
+			t.Errorf("attribute %s = %v (%s), want %v (%s)", key, got.attrs[key].Emit(), got.attrs[key].Type(), value.Emit(), value.Type())
+		}
+	}
+	if len(got.errs) != 1 || got.status != codes.Error || got.message != "failed" {
+		t.Errorf("span has errors %v and status %v %q, want one error and status Error \"failed\"", got.errs, got.status, got.message)
+	}
+	if !got.ended {
+		t.Error("span was not ended")
+	}
+}
+
+func TestMeterRecordsMeasurements(t *testing.T) {
+	provider := &meterProvider{meter: &recordingMeter{}}
+	meter := otelirembopay.NewMeter(provider)
+	if provider.scope != otelirembopay.ScopeName {
+		t.Errorf("meter scope = %q, want %q", provider.scope, otelirembopay.ScopeName)
+	}
+
+	ctx := context.Background()
+	meter.Counter("requests", "Requests").Add(ctx, 2, irembopay.Attribute{Key: "operation", Value: "invoice.get"})
+	meter.Histogram("duration", "Duration", "s").Record(ctx, 0.25, irembopay.Attribute{Key: "status", Value: 200})
+
+	recorded := provider.meter
+	if recorded.descriptions["requests"] != "Requests" || recorded.descriptions["duration"] != "Duration" {
+		t.Errorf("descriptions = %v, want Requests and Duration", recorded.descriptions)
+	}
+	want := []measurement{
+		{name: "requests", value: 2, attrs: attribute.NewSet(attribute.String("operation", "invoice.get"))},
+		{name: "duration", value: 0.25, attrs: attribute.NewSet(attribute.Int("status", 200))},
+	}
+	if len(recorded.measurements) != len(want) {
+		t.Fatalf("recorded %d measurements, want %d", len(recorded.measurements), len(want))
+	}
+	for i, m := range recorded.measurements {
+		if m.name != want[i].name || m.value != want[i].value || !m.attrs.Equals(&want[i].attrs) {
+			t.Errorf("measurement %d = %s %v %v, want %s %v %v", i, m.name, m.value, m.attrs.ToSlice(), want[i].name, want[i].value, want[i].attrs.ToSlice())
+		}
+	}
+}
+
+func TestClientInstrumentation(t *testing.T) {
+	srv := irembopaytest.NewServer("test-secret-key")
+	defer srv.Close()
+
+	tracer := &recordingTracer{}
+	meter := &recordingMeter{}
+	client, err := srv.NewClient(
+		irembopay.WithTracer(otelirembopay.NewTracer(&tracerProvider{tracer: tracer})),
+		irembopay.WithMeter(otelirembopay.NewMeter(&meterProvider{meter: meter})),
+	)
+	if err != nil {
+		t.Fatalf("NewClient returned error: %v", err)
+	}
+
+	if _, err := client.Invoice.Get(context.Background(), "unknown"); !irembopay.IsNotFoundError(err) {
+		t.Fatalf("Get returned %v, want a not found error", err)
+	}
+
+	if len(tracer.spans) != 1 {
+		t.Fatalf("recorded %d spans, want 1", len(tracer.spans))
+	}
+	span := tracer.spans[0]
+	if span.name != "irembopay invoice.get" || !span.ended {
+		t.Errorf("span %q ended: %v, want an ended irembopay invoice.get span", span.name, span.ended)
+	}
+	if got := span.attrs[irembopay.AttrErrorType].AsString(); got != "not_found" {
+		t.Errorf("span %s = %q, want not_found", irembopay.AttrErrorType, got)
+	}
+	if got := span.attrs[irembopay.AttrStatusCode].AsInt64(); got != 404 {
+		t.Errorf("span %s = %d, want 404", irembopay.AttrStatusCode, got)
+	}
+
+	names := make(map[string]bool)
+	for _, m := range meter.measurements {
+		names[m.name] = true
+	}
+	if !names[irembopay.MetricRequests] || !names[irembopay.MetricRequestDuration] {
+		t.Errorf("recorded metrics %v, want %s and %s", names, irembopay.MetricRequests, irembopay.MetricRequestDuration)
+	}
+}
@@ -11,6 +11,8 @@ import (
 	"strconv"
 	"strings"
 	"time"
+
+	"github.com/cruso003/irembopay/phone"
 )
 
 // PaymentService handles payment operations
@@ -29,11 +31,16 @@ func NewPaymentService(client *Client, config *Config) *PaymentService {
 
 // InitiateMomoPayment initiates a mobile money payment
 func (s *PaymentService) InitiateMomoPayment(ctx context.Context, req *MomoPaymentRequest) (*MomoPaymentResponse, error) {
+	if err := validateRequest(s.config, req); err != nil {
+		return nil, fmt.Errorf("failed to initiate mobile money payment: %w", err)
+	}
+
 	var response MomoPaymentResponse
 	apiReq := Request{
-		Method: http.MethodPost,
-		Path:   "/payments/transactions/initiate",
-		Body:   req,
+		Operation: "payment.initiate_momo",
+		Method:    http.MethodPost,
+		Path:      "/payments/transactions/initiate",
+		Body:      req,
 	}
 
 	err := s.client.DoRequest(ctx, apiReq, &response)
@@ -44,13 +51,65 @@ func (s *PaymentService) InitiateMomoPayment(ctx context.Context, req *MomoPayme
 	return &response, nil
 }
 
+// InitiateMomoPaymentAutoDetect initiates a mobile money payment after
+// normalizing the phone number of the request and, if the payment provider is
+// not set, detecting it from the number. Malformed numbers, and providers that
+// do not match the operator of the number, are rejected with a ValidationError
+// before calling the API.
+func (s *PaymentService) InitiateMomoPaymentAutoDetect(ctx context.Context, req *MomoPaymentRequest) (*MomoPaymentResponse, error) {
+	if req == nil {
+		return s.InitiateMomoPayment(ctx, req)
+	}
+
+	number, err := phone.Parse(req.AccountIdentifier)
+	if err != nil {
+		v := &ValidationError{}
+		v.add("accountIdentifier", phoneErrorMessage(err))
+		return nil, fmt.Errorf("failed to initiate mobile money payment: %w", v)
+	}
+
+	detected := *req
+	detected.AccountIdentifier = number.Local()
+	operator := PaymentProvider(number.Operator())
+	switch detected.PaymentProvider {
+	case "":
+		detected.PaymentProvider = operator
+	case operator:
+	default:
+		v := &ValidationError{}
+		v.add("paymentProvider", "is %s but accountIdentifier is a %s number", detected.PaymentProvider, operator)
+		return nil, fmt.Errorf("failed to initiate mobile money payment: %w", v)
+	}
+
+	return s.InitiateMomoPayment(ctx, &detected)
+}
+
+// DetectPaymentProvider returns the mobile money provider of a Rwandan phone number
+func DetectPaymentProvider(phoneNumber string) (PaymentProvider, error) {
+	number, err := phone.Parse(phoneNumber)
+	if err != nil {
+		return "", err
+	}
+	return PaymentProvider(number.Operator()), nil
+}
+
 // VerifyWebhookSignature verifies the signature of a webhook notification
+// against the current secret key and any previous secret keys
 func (s *PaymentService) VerifyWebhookSignature(signature, payload string) (bool, error) {
+	_, valid, err := s.MatchWebhookSignature(signature, payload)
+	return valid, err
+}
+
+// MatchWebhookSignature verifies the signature of a webhook notification and
+// reports which secret key matched: 0 for the current secret key, and i for
+// the i-th previous secret key configured with WithPreviousSecretKeys.
+// The index is -1 if no key matched.
+func (s *PaymentService) MatchWebhookSignature(signature, payload string) (int, bool, error) {
 	// Parse the signature header
 	// Format: t=<timestamp>, s=<signature>
 	parts := strings.Split(signature, ",")
 	if len(parts) != 2 {
-		return false, fmt.Errorf("invalid signature format")
+		return -1, false, fmt.Errorf("invalid signature format")
 	}
 
 	var timestamp, sig string
@@ -64,16 +123,26 @@ func (s *PaymentService) VerifyWebhookSignature(signature, payload string) (bool
 	}
 
 	if timestamp == "" || sig == "" {
-		return false, fmt.Errorf("missing timestamp or signature")
+		return -1, false, fmt.Errorf("missing timestamp or signature")
 	}
 
-	// Verify the signature
+	sigBytes, err := hex.DecodeString(sig)
+	if err != nil {
+		return -1, false, nil
+	}
+
+	// Verify the signature against every active key
 	// signature = HMAC_SHA256(secretKey, timestamp + "#" + payload)
-	mac := hmac.New(sha256.New, []byte(s.config.SecretKey))
-	mac.Write([]byte(timestamp + "#" + payload))
-	expectedSig := hex.EncodeToString(mac.Sum(nil))
+	keys := append([]string{s.config.SecretKey}, s.config.PreviousSecretKeys...)
+	for i, key := range keys {
+		mac := hmac.New(sha256.New, []byte(key))
+		mac.Write([]byte(timestamp + "#" + payload))
+		if hmac.Equal(mac.Sum(nil), sigBytes) {
+			return i, true, nil
+		}
+	}
 
-	return expectedSig == sig, nil
+	return -1, false, nil
 }
 
 // ParseNotification parses a payment notification from a webhook payload
@@ -87,7 +156,10 @@ func (s *PaymentService) ParseNotification(payload string) (*PaymentNotification
 	return &notification, nil
 }
 
-// HandleWebhook is a utility function to handle webhook notifications
+// HandleWebhook is a utility function to handle webhook notifications.
+// Notifications signed longer ago than the replay TTL are rejected, and with a
+// replay store, deliveries that were already accepted return
+// ErrDuplicateNotification.
 func (s *PaymentService) HandleWebhook(signature, payload string) (*PaymentNotification, error) {
 	// Verify the signature
 	valid, err := s.VerifyWebhookSignature(signature, payload)
@@ -98,15 +170,81 @@ func (s *PaymentService) HandleWebhook(signature, payload string) (*PaymentNotif
 		return nil, fmt.Errorf("invalid signature")
 	}
 
+	// Reject deliveries older than the replay store remembers them
+	valid, err = s.ValidateWebhookTimestamp(signature, s.replayTTL())
+	if err != nil {
+		return nil, fmt.Errorf("failed to validate timestamp: %w", err)
+	}
+	if !valid {
+		return nil, fmt.Errorf("timestamp is too old")
+	}
+
 	// Parse the notification
 	notification, err := s.ParseNotification(payload)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse notification: %w", err)
 	}
 
+	// Reject deliveries that were already accepted
+	ctx := context.Background()
+	state, err := s.claimReplay(ctx, signature, s.replayTTL())
+	if err != nil {
+		return nil, err
+	}
+	if state != ReplayNew {
+		return nil, ErrDuplicateNotification
+	}
+	if err := s.completeReplay(ctx, signature); err != nil {
+		return nil, err
+	}
+
 	return notification, nil
 }
 
+// ReleaseWebhook forgets a delivery accepted by HandleWebhook, so that it is
+// accepted again when IremboPay retries it. Call it when the notification
+// could not be processed. It does nothing without a replay store.
+func (s *PaymentService) ReleaseWebhook(ctx context.Context, signature string) error {
+	if s.config.ReplayStore == nil {
+		return nil
+	}
+	return s.config.ReplayStore.Forget(ctx, replayKey(signature))
+}
+
+// replayTTL returns the time a processed delivery is remembered
+func (s *PaymentService) replayTTL() time.Duration {
+	if s.config.ReplayTTL <= 0 {
+		return DefaultReplayTTL
+	}
+	return s.config.ReplayTTL
+}
+
+// claimReplay claims the delivery in the replay store for the lease duration.
+// It returns ReplayNew without a replay store.
+func (s *PaymentService) claimReplay(ctx context.Context, signature string, lease time.Duration) (ReplayState, error) {
+	if s.config.ReplayStore == nil {
+		return ReplayNew, nil
+	}
+
+	state, err := s.config.ReplayStore.Claim(ctx, replayKey(signature), lease)
+	if err != nil {
+		return ReplayNew, fmt.Errorf("failed to check replay store: %w", err)
+	}
+	return state, nil
+}
+
+// completeReplay records the delivery as processed in the replay store
+func (s *PaymentService) completeReplay(ctx context.Context, signature string) error {
+	if s.config.ReplayStore == nil {
+		return nil
+	}
+
+	if err := s.config.ReplayStore.Complete(ctx, replayKey(signature), s.replayTTL()); err != nil {
+		return fmt.Errorf("failed to record processed delivery: %w", err)
+	}
+	return nil
+}
+
 // ValidateWebhookTimestamp validates that the webhook timestamp is not too old
 // to prevent replay attacks
 func (s *PaymentService) ValidateWebhookTimestamp(signature string, maxAge time.Duration) (bool, error) {
@@ -0,0 +1,81 @@
+package irembopay_test
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/cruso003/irembopay"
+	"github.com/cruso003/irembopay/irembopaytest"
+)
+
+func TestInitiateMomoPaymentAutoDetect(t *testing.T) {
+	srv := irembopaytest.NewServer("test-secret-key")
+	defer srv.Close()
+
+	// Count the payment requests reaching the server
+	var initiated atomic.Int32
+	client, err := srv.NewClient(irembopay.WithMiddleware(func(next http.RoundTripper) http.RoundTripper {
+		return irembopay.RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			if strings.HasSuffix(req.URL.Path, "/payments/transactions/initiate") {
+				initiated.Add(1)
+			}
+			return next.RoundTrip(req)
+		})
+	}))
+	if err != nil {
+		t.Fatalf("NewClient returned error: %v", err)
+	}
+	ctx := context.Background()
+
+	invoice, err := client.Invoice.Create(ctx, invoiceRequest())
+	if err != nil {
+		t.Fatalf("Create returned error: %v", err)
+	}
+
+	tests := []struct {
+		name         string
+		phone        string
+		provider     irembopay.PaymentProvider
+		wantAccount  string
+		wantProvider irembopay.PaymentProvider
+		wantErr      bool
+	}{
+		{name: "MTN number", phone: "+250 78 123 4567", wantAccount: "0781234567", wantProvider: irembopay.PaymentProviderMTN},
+		{name: "Airtel number", phone: "250731234567", wantAccount: "0731234567", wantProvider: irembopay.PaymentProviderAirtel},
+		{name: "matching provider", phone: "0721234567", provider: irembopay.PaymentProviderAirtel, wantAccount: "0721234567", wantProvider: irembopay.PaymentProviderAirtel},
+		{name: "MTN provider on an Airtel number", phone: "0721234567", provider: irembopay.PaymentProviderMTN, wantErr: true},
+		{name: "Airtel provider on an MTN number", phone: "0791234567", provider: irembopay.PaymentProviderAirtel, wantErr: true},
+		{name: "unknown operator", phone: "0751234567", wantErr: true},
+		{name: "foreign number", phone: "+33612345678", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before := initiated.Load()
+			resp, err := client.Payment.InitiateMomoPaymentAutoDetect(ctx, &irembopay.MomoPaymentRequest{
+				AccountIdentifier: tt.phone,
+				PaymentProvider:   tt.provider,
+				InvoiceNumber:     invoice.InvoiceNumber,
+			})
+			if tt.wantErr {
+				if !irembopay.IsValidationError(err) {
+					t.Fatalf("InitiateMomoPaymentAutoDetect returned %v, want a validation error", err)
+				}
+				if initiated.Load() != before {
+					t.Error("a rejected payment was sent to the API")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("InitiateMomoPaymentAutoDetect returned error: %v", err)
+			}
+			if resp.AccountIdentifier != tt.wantAccount || resp.PaymentProvider != tt.wantProvider {
+				t.Errorf("payment initiated for %s with %s, want %s with %s",
+					resp.AccountIdentifier, resp.PaymentProvider, tt.wantAccount, tt.wantProvider)
+			}
+		})
+	}
+}
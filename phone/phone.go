@@ -0,0 +1,135 @@
+// Package phone normalizes and validates Rwandan mobile phone numbers (MSISDNs)
+// and identifies their mobile money operator.
+package phone
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// CountryCode is the international calling code of Rwanda
+const CountryCode = "250"
+
+// Errors returned by Parse
+var (
+	ErrInvalidNumber   = errors.New("invalid Rwandan mobile number")
+	ErrUnknownOperator = errors.New("unknown mobile operator")
+)
+
+// Operator represents a Rwandan mobile network operator
+type Operator string
+
+const (
+	// MTN is MTN Rwanda (078 and 079 numbers)
+	MTN Operator = "MTN"
+	// Airtel is Airtel Rwanda (072 and 073 numbers)
+	Airtel Operator = "AIRTEL"
+)
+
+// operatorPrefixes maps the first two digits of national numbers to their operator
+var operatorPrefixes = map[string]Operator{
+	"78": MTN,
+	"79": MTN,
+	"72": Airtel,
+	"73": Airtel,
+}
+
+// Number is a normalized Rwandan mobile number: the 9-digit national number
+// without the leading 0, e.g. 781234567
+type Number string
+
+// Parse normalizes a mobile number given as 07XXXXXXXX, 7XXXXXXXX,
+// +2507XXXXXXXX, 2507XXXXXXXX or 002507XXXXXXXX. Spaces, dashes, dots and
+// parentheses are ignored. It returns ErrInvalidNumber for malformed numbers
+// and ErrUnknownOperator for numbers that no operator issues.
+func Parse(s string) (Number, error) {
+	digits := strings.Map(func(r rune) rune {
+		switch r {
+		case ' ', '-', '.', '(', ')':
+			return -1
+		}
+		return r
+	}, strings.TrimSpace(s))
+
+	switch {
+	case strings.HasPrefix(digits, "+"+CountryCode):
+		digits = strings.TrimPrefix(digits, "+"+CountryCode)
+	case strings.HasPrefix(digits, "00"+CountryCode):
+		digits = strings.TrimPrefix(digits, "00"+CountryCode)
+	case strings.HasPrefix(digits, CountryCode) && len(digits) > 10:
+		digits = strings.TrimPrefix(digits, CountryCode)
+	}
+	// Drop the trunk prefix of local numbers, also found in forms like +250 (0)78...
+	digits = strings.TrimPrefix(digits, "0")
+
+	if len(digits) != 9 || digits[0] != '7' || !isDigits(digits) {
+		return "", fmt.Errorf("%w: %q", ErrInvalidNumber, s)
+	}
+
+	number := Number(digits)
+	if number.Operator() == "" {
+		return "", fmt.Errorf("%w: %q", ErrUnknownOperator, s)
+	}
+
+	return number, nil
+}
+
+// Normalize returns the local form (07XXXXXXXX) of a mobile number
+func Normalize(s string) (string, error) {
+	number, err := Parse(s)
+	if err != nil {
+		return "", err
+	}
+	return number.Local(), nil
+}
+
+// Valid checks if the string is a valid Rwandan mobile number
+func Valid(s string) bool {
+	_, err := Parse(s)
+	return err == nil
+}
+
+// Operator returns the operator that issued the number, or an empty operator if unknown
+func (n Number) Operator() Operator {
+	if len(n) < 2 {
+		return ""
+	}
+	return operatorPrefixes[string(n[:2])]
+}
+
+// Local returns the number in local form, e.g. 0781234567
+func (n Number) Local() string {
+	return "0" + string(n)
+}
+
+// International returns the number with the country code, e.g. 250781234567
+func (n Number) International() string {
+	if n == "" {
+		return ""
+	}
+	return CountryCode + string(n)
+}
+
+// E164 returns the number in E.164 form, e.g. +250781234567
+func (n Number) E164() string {
+	if n == "" {
+		return ""
+	}
+	return "+" + n.International()
+}
+
+// String returns the number in E.164 form
+func (n Number) String() string {
+	return n.E164()
+}
+
+// isDigits checks if the string only contains ASCII digits
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
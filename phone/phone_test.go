@@ -0,0 +1,91 @@
+package phone_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/cruso003/irembopay/phone"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		input    string
+		want     phone.Number
+		operator phone.Operator
+		wantErr  error
+	}{
+		{input: "0781234567", want: "781234567", operator: phone.MTN},
+		{input: "0791234567", want: "791234567", operator: phone.MTN},
+		{input: "0721234567", want: "721234567", operator: phone.Airtel},
+		{input: "0731234567", want: "731234567", operator: phone.Airtel},
+		{input: "781234567", want: "781234567", operator: phone.MTN},
+		{input: "+250781234567", want: "781234567", operator: phone.MTN},
+		{input: "250731234567", want: "731234567", operator: phone.Airtel},
+		{input: "00250781234567", want: "781234567", operator: phone.MTN},
+		{input: "+250 (0)78 123 4567", want: "781234567", operator: phone.MTN},
+		{input: " 078-123.4567 ", want: "781234567", operator: phone.MTN},
+
+		// Wrong lengths
+		{input: "078123456", wantErr: phone.ErrInvalidNumber},
+		{input: "07812345678", wantErr: phone.ErrInvalidNumber},
+		{input: "+25078123456", wantErr: phone.ErrInvalidNumber},
+		{input: "", wantErr: phone.ErrInvalidNumber},
+
+		// Not mobile numbers, or not issued by MTN or Airtel
+		{input: "0252123456", wantErr: phone.ErrInvalidNumber},
+		{input: "0751234567", wantErr: phone.ErrUnknownOperator},
+		{input: "+250701234567", wantErr: phone.ErrUnknownOperator},
+
+		// Malformed and foreign numbers
+		{input: "07812a4567", wantErr: phone.ErrInvalidNumber},
+		{input: "+33612345678", wantErr: phone.ErrInvalidNumber},
+		{input: "+256781234567", wantErr: phone.ErrInvalidNumber},
+		{input: "+1 (415) 555-0100", wantErr: phone.ErrInvalidNumber},
+	}
+
+	for _, tt := range tests {
+		got, err := phone.Parse(tt.input)
+		if tt.wantErr != nil {
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Parse(%q) = %q, %v, want %v", tt.input, got, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Parse(%q) returned error: %v", tt.input, err)
+			continue
+		}
+		if got != tt.want || got.Operator() != tt.operator {
+			t.Errorf("Parse(%q) = %q (%s), want %q (%s)", tt.input, got, got.Operator(), tt.want, tt.operator)
+		}
+	}
+}
+
+func TestNumberFormats(t *testing.T) {
+	number := phone.Number("781234567")
+
+	if got := number.Local(); got != "0781234567" {
+		t.Errorf("Local() = %q, want 0781234567", got)
+	}
+	if got := number.International(); got != "250781234567" {
+		t.Errorf("International() = %q, want 250781234567", got)
+	}
+	if got := number.E164(); got != "+250781234567" {
+		t.Errorf("E164() = %q, want +250781234567", got)
+	}
+	if got := phone.Number("").E164(); got != "" {
+		t.Errorf("E164() of an empty number = %q, want empty", got)
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	if got, err := phone.Normalize("+250 78 123 4567"); err != nil || got != "0781234567" {
+		t.Errorf("Normalize = %q, %v, want 0781234567", got, err)
+	}
+	if _, err := phone.Normalize("12345"); err == nil {
+		t.Error("Normalize of a malformed number should fail")
+	}
+	if !phone.Valid("0721234567") || phone.Valid("0751234567") {
+		t.Error("Valid should accept Airtel numbers and reject unknown operators")
+	}
+}
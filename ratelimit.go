@@ -0,0 +1,179 @@
+package irembopay
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// rateLimiter is a token bucket shared by all the requests of a client.
+// It also pauses all requests when the API reports that the rate limit is
+// exhausted.
+type rateLimiter struct {
+	mu          sync.Mutex
+	rate        float64   // Tokens added per second
+	burst       float64   // Maximum number of tokens
+	tokens      float64   // Available tokens, negative when requests are waiting
+	last        time.Time // Time up to which tokens were added, in the future while paused
+	pausedUntil time.Time // End of the latest pause
+	now         func() time.Time
+}
+
+// newRateLimiter creates a token bucket allowing rps requests per second on
+// average and bursts of up to burst requests
+func newRateLimiter(rps float64, burst int) *rateLimiter {
+	if burst < 1 {
+		burst = 1
+	}
+	return &rateLimiter{
+		rate:   rps,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+		now:    time.Now,
+	}
+}
+
+// reservation is a token reserved by a request
+type reservation struct {
+	ready  time.Time     // Time at which the request may be sent
+	offset time.Duration // Place in the queue, as a delay after the end of a pause
+	paused time.Time     // End of the latest pause known to the reservation
+}
+
+// wait blocks until a request may be sent or the context is done
+func (l *rateLimiter) wait(ctx context.Context) error {
+	r := l.reserve()
+	for {
+		delay := l.delay(&r)
+		if delay <= 0 {
+			return nil
+		}
+		if err := sleepContext(ctx, delay); err != nil {
+			l.cancel()
+			return err
+		}
+	}
+}
+
+// reserve reserves a token, to be used once the reservation is ready
+func (l *rateLimiter) reserve() reservation {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.refill(l.now())
+
+	// Requests waiting for a token get one every 1/rate seconds after last
+	l.tokens--
+	var offset time.Duration
+	if l.tokens < 0 {
+		offset = time.Duration(-l.tokens / l.rate * float64(time.Second))
+	}
+	return reservation{ready: l.last.Add(offset), offset: offset, paused: l.pausedUntil}
+}
+
+// delay returns the time left before the reservation is ready. A reservation
+// made before a pause keeps its place in the queue after the pause.
+func (l *rateLimiter) delay(r *reservation) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.pausedUntil.After(r.paused) {
+		r.paused = l.pausedUntil
+		if ready := r.paused.Add(r.offset); ready.After(r.ready) {
+			r.ready = ready
+		}
+	}
+	return r.ready.Sub(l.now())
+}
+
+// cancel gives back the token of a reservation that will not be used
+func (l *rateLimiter) cancel() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.tokens = min(l.tokens+1, l.burst)
+}
+
+// refill adds the tokens accumulated since the last update
+func (l *rateLimiter) refill(now time.Time) {
+	if elapsed := now.Sub(l.last); elapsed > 0 {
+		l.tokens = min(l.tokens+elapsed.Seconds()*l.rate, l.burst)
+		l.last = now
+	}
+}
+
+// observe pauses the limiter when a response reports that the rate limit is
+// exhausted, either with a 429 and a Retry-After header or with
+// X-RateLimit-Remaining: 0 and an X-RateLimit-Reset header
+func (l *rateLimiter) observe(resp *http.Response) {
+	if resp == nil {
+		return
+	}
+
+	now := l.now()
+	var until time.Time
+	if resp.StatusCode == http.StatusTooManyRequests {
+		if delay, ok := (RetryPolicy{}).retryAfter(resp); ok {
+			until = now.Add(delay)
+		}
+	}
+	if resp.Header.Get("X-RateLimit-Remaining") == "0" {
+		if reset, ok := rateLimitReset(resp.Header.Get("X-RateLimit-Reset"), now); ok && reset.After(until) {
+			until = reset
+		}
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if !until.After(l.pausedUntil) {
+		return
+	}
+	l.pausedUntil = until
+
+	// Empty the bucket and only start refilling it when the pause ends, so
+	// that the requests queued in the meantime are spread out afterwards
+	l.refill(now)
+	if until.After(l.last) {
+		l.last = until
+	}
+	l.tokens = min(l.tokens, 0)
+}
+
+// rateLimitReset parses an X-RateLimit-Reset header, given either as a number
+// of seconds until the reset or as a Unix timestamp
+func rateLimitReset(value string, now time.Time) (time.Time, bool) {
+	seconds, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || seconds < 0 {
+		return time.Time{}, false
+	}
+
+	// Values larger than a day of seconds are Unix timestamps
+	if seconds > 24*60*60 {
+		return time.Unix(seconds, 0), true
+	}
+	return now.Add(time.Duration(seconds) * time.Second), true
+}
+
+// acquire waits for a free slot when the number of concurrent requests is
+// capped, and returns the function releasing it
+func (c *Client) acquire(ctx context.Context) (func(), error) {
+	if c.limiter != nil {
+		if err := c.limiter.wait(ctx); err != nil {
+			return nil, fmt.Errorf("error waiting for rate limiter: %w", err)
+		}
+	}
+
+	if c.slots == nil {
+		return func() {}, nil
+	}
+	select {
+	case c.slots <- struct{}{}:
+		return func() { <-c.slots }, nil
+	case <-ctx.Done():
+		return nil, fmt.Errorf("error waiting for a concurrency slot: %w", ctx.Err())
+	}
+}
@@ -0,0 +1,181 @@
+package irembopay
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newTestLimiter creates a rate limiter whose clock is read from now
+func newTestLimiter(rps float64, burst int, now *time.Time) *rateLimiter {
+	l := newRateLimiter(rps, burst)
+	l.now = func() time.Time { return *now }
+	l.last = *now
+	return l
+}
+
+// reserveDelays reserves n tokens and returns the delay of each reservation
+func reserveDelays(l *rateLimiter, n int) []time.Duration {
+	delays := make([]time.Duration, n)
+	for i := range delays {
+		r := l.reserve()
+		delays[i] = l.delay(&r).Round(time.Millisecond)
+	}
+	return delays
+}
+
+// checkDelays compares the delays of reservations to the expected ones
+func checkDelays(t *testing.T, got []time.Duration, want ...time.Duration) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("got %d delays, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("delays = %v, want %v", got, want)
+			return
+		}
+	}
+}
+
+// rateLimitedResponse returns a response with the status code and headers
+func rateLimitedResponse(statusCode int, headers ...string) *http.Response {
+	resp := &http.Response{StatusCode: statusCode, Header: make(http.Header)}
+	for i := 0; i+1 < len(headers); i += 2 {
+		resp.Header.Set(headers[i], headers[i+1])
+	}
+	return resp
+}
+
+func TestRateLimiterBurstAndRate(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	l := newTestLimiter(10, 2, &now)
+
+	checkDelays(t, reserveDelays(l, 4), 0, 0, 100*time.Millisecond, 200*time.Millisecond)
+
+	// Idle time refills the bucket up to the burst
+	now = now.Add(10 * time.Second)
+	checkDelays(t, reserveDelays(l, 3), 0, 0, 100*time.Millisecond)
+}
+
+func TestRateLimiterPauseSpreadsQueuedRequests(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	l := newTestLimiter(10, 5, &now)
+
+	l.observe(rateLimitedResponse(http.StatusTooManyRequests, "Retry-After", "60"))
+
+	// No tokens are added during the pause, so the requests sent when it ends
+	// are spaced by the rate instead of sent at once
+	now = now.Add(30 * time.Second)
+	checkDelays(t, reserveDelays(l, 3), 30100*time.Millisecond, 30200*time.Millisecond, 30300*time.Millisecond)
+
+	now = now.Add(30 * time.Second)
+	checkDelays(t, reserveDelays(l, 2), 400*time.Millisecond, 500*time.Millisecond)
+}
+
+func TestRateLimiterPauseKeepsQueueOrder(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	l := newTestLimiter(10, 1, &now)
+
+	first, second := l.reserve(), l.reserve()
+	if d := l.delay(&first); d != 0 {
+		t.Fatalf("first delay = %v, want 0", d)
+	}
+	if d := l.delay(&second); d != 100*time.Millisecond {
+		t.Fatalf("second delay = %v, want 100ms", d)
+	}
+
+	l.observe(rateLimitedResponse(http.StatusOK, "X-RateLimit-Remaining", "0", "X-RateLimit-Reset", "1"))
+
+	// The waiting request moves after the pause, ahead of later requests
+	if d := l.delay(&second); d != 1100*time.Millisecond {
+		t.Errorf("second delay after a pause = %v, want 1.1s", d)
+	}
+	checkDelays(t, reserveDelays(l, 1), 1200*time.Millisecond)
+}
+
+func TestRateLimiterIgnoresResponsesWithinLimits(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	l := newTestLimiter(10, 1, &now)
+
+	l.observe(rateLimitedResponse(http.StatusOK, "X-RateLimit-Remaining", "5", "X-RateLimit-Reset", "60"))
+	l.observe(rateLimitedResponse(http.StatusServiceUnavailable, "Retry-After", "60"))
+	l.observe(nil)
+
+	checkDelays(t, reserveDelays(l, 2), 0, 100*time.Millisecond)
+}
+
+func TestRateLimiterCancelIsCappedByBurst(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	l := newTestLimiter(10, 2, &now)
+
+	l.reserve()
+	l.cancel()
+	l.cancel()
+	checkDelays(t, reserveDelays(l, 3), 0, 0, 100*time.Millisecond)
+}
+
+func TestMaxConcurrency(t *testing.T) {
+	var inFlight, peak atomic.Int32
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		<-release
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"success":true,"message":"Success","data":{"invoiceNumber":"880419623157"}}`))
+	}))
+	defer srv.Close()
+
+	baseURL, _ := url.Parse(srv.URL)
+	config, err := NewConfig(Sandbox, "test-secret-key", WithBaseURL(baseURL), WithMaxConcurrency(2))
+	if err != nil {
+		t.Fatalf("NewConfig returned error: %v", err)
+	}
+	client := NewIremboPay(config)
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 5)
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := client.Invoice.Get(context.Background(), "880419623157")
+			errs <- err
+		}()
+	}
+
+	// Requests over the cap wait for a slot until their context is done
+	time.Sleep(50 * time.Millisecond)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err = client.Invoice.Get(ctx, "880419623157")
+	if !errors.Is(err, context.DeadlineExceeded) || !strings.Contains(err.Error(), "concurrency slot") {
+		t.Errorf("Get over the cap returned %v, want a concurrency slot timeout", err)
+	}
+
+	close(release)
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Errorf("Get returned error: %v", err)
+		}
+	}
+	if got := peak.Load(); got != 2 {
+		t.Errorf("peak concurrency = %d, want 2", got)
+	}
+}
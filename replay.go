@@ -0,0 +1,281 @@
+package irembopay
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultReplayTTL is the default time a webhook delivery is remembered
+const DefaultReplayTTL = 24 * time.Hour
+
+// ReplayState is the state of a webhook delivery in a ReplayStore
+type ReplayState int
+
+const (
+	// ReplayNew is a delivery that is not recorded, or whose record expired
+	ReplayNew ReplayState = iota
+	// ReplayInProgress is a delivery that is being processed
+	ReplayInProgress
+	// ReplayDone is a delivery that was processed
+	ReplayDone
+)
+
+// ReplayStore records the webhook deliveries that are being processed or were
+// already processed, so that a captured request replayed later is detected as
+// a duplicate and a retry does not run concurrently with the first attempt
+type ReplayStore interface {
+	// Claim records the key as in progress for the lease duration unless it is
+	// already recorded and has not expired yet. It returns the state the key had
+	// before the call: ReplayNew means the caller claimed it. It must be atomic.
+	Claim(ctx context.Context, key string, lease time.Duration) (ReplayState, error)
+
+	// Complete records the key as processed for the given duration
+	Complete(ctx context.Context, key string, ttl time.Duration) error
+
+	// Forget removes the key, so that a delivery that could not be processed
+	// is accepted again when IremboPay retries it
+	Forget(ctx context.Context, key string) error
+}
+
+// replayKey derives the replay store key from a signature header.
+// The header contains the timestamp and the HMAC of the payload, so it is
+// unique to a single delivery.
+func replayKey(signature string) string {
+	sum := sha256.Sum256([]byte(strings.TrimSpace(signature)))
+	return hex.EncodeToString(sum[:])
+}
+
+// MemoryReplayStore is an in-memory ReplayStore that evicts the least recently
+// recorded keys once its capacity is reached
+type MemoryReplayStore struct {
+	mu       sync.Mutex
+	capacity int
+	entries  map[string]*list.Element
+	order    *list.List // Front is the most recently recorded key
+	now      func() time.Time
+}
+
+// memoryReplayEntry is a key recorded in a MemoryReplayStore
+type memoryReplayEntry struct {
+	key       string
+	state     ReplayState
+	expiresAt time.Time
+}
+
+// NewMemoryReplayStore creates an in-memory replay store holding at most capacity keys
+func NewMemoryReplayStore(capacity int) *MemoryReplayStore {
+	if capacity <= 0 {
+		capacity = 10000
+	}
+	return &MemoryReplayStore{
+		capacity: capacity,
+		entries:  make(map[string]*list.Element),
+		order:    list.New(),
+		now:      time.Now,
+	}
+}
+
+// Claim implements the ReplayStore interface
+func (s *MemoryReplayStore) Claim(ctx context.Context, key string, lease time.Duration) (ReplayState, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	if elem, ok := s.entries[key]; ok {
+		entry := elem.Value.(*memoryReplayEntry)
+		if now.Before(entry.expiresAt) {
+			return entry.state, nil
+		}
+	}
+
+	// The key is new or expired, record it again
+	s.record(key, ReplayInProgress, now.Add(lease))
+	return ReplayNew, nil
+}
+
+// Complete implements the ReplayStore interface
+func (s *MemoryReplayStore) Complete(ctx context.Context, key string, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.record(key, ReplayDone, s.now().Add(ttl))
+	return nil
+}
+
+// record records the key with the state until expiresAt
+func (s *MemoryReplayStore) record(key string, state ReplayState, expiresAt time.Time) {
+	if elem, ok := s.entries[key]; ok {
+		entry := elem.Value.(*memoryReplayEntry)
+		entry.state, entry.expiresAt = state, expiresAt
+		s.order.MoveToFront(elem)
+		return
+	}
+
+	s.entries[key] = s.order.PushFront(&memoryReplayEntry{key: key, state: state, expiresAt: expiresAt})
+
+	// Evict the oldest keys once the capacity is exceeded
+	for s.order.Len() > s.capacity {
+		oldest := s.order.Back()
+		s.order.Remove(oldest)
+		delete(s.entries, oldest.Value.(*memoryReplayEntry).key)
+	}
+}
+
+// Forget implements the ReplayStore interface
+func (s *MemoryReplayStore) Forget(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if elem, ok := s.entries[key]; ok {
+		s.order.Remove(elem)
+		delete(s.entries, key)
+	}
+	return nil
+}
+
+// Len returns the number of keys currently held by the store
+func (s *MemoryReplayStore) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.order.Len()
+}
+
+// tableNamePattern restricts table names to plain SQL identifiers
+var tableNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_.]*$`)
+
+// SQLReplayStore is a ReplayStore backed by a database/sql table, so that
+// duplicates are detected across several instances of an application
+type SQLReplayStore struct {
+	db          *sql.DB
+	table       string
+	placeholder func(n int) string
+	now         func() time.Time
+}
+
+// SQLReplayStoreOption defines a function type for setting SQL replay store options
+type SQLReplayStoreOption func(*SQLReplayStore)
+
+// WithDollarPlaceholders uses $1, $2... query placeholders, as required by PostgreSQL
+func WithDollarPlaceholders() SQLReplayStoreOption {
+	return func(s *SQLReplayStore) {
+		s.placeholder = func(n int) string {
+			return fmt.Sprintf("$%d", n)
+		}
+	}
+}
+
+// NewSQLReplayStore creates a replay store using the given table.
+// Queries use ? placeholders unless WithDollarPlaceholders is set.
+func NewSQLReplayStore(db *sql.DB, table string, opts ...SQLReplayStoreOption) (*SQLReplayStore, error) {
+	if db == nil {
+		return nil, fmt.Errorf("database is required")
+	}
+	if !tableNamePattern.MatchString(table) {
+		return nil, fmt.Errorf("invalid table name: %q", table)
+	}
+
+	s := &SQLReplayStore{
+		db:    db,
+		table: table,
+		placeholder: func(int) string {
+			return "?"
+		},
+		now: time.Now,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s, nil
+}
+
+// CreateTable creates the replay table if it does not exist
+func (s *SQLReplayStore) CreateTable(ctx context.Context) error {
+	query := fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+	replay_key VARCHAR(64) NOT NULL PRIMARY KEY,
+	state SMALLINT NOT NULL,
+	expires_at BIGINT NOT NULL
+)`, s.table)
+
+	if _, err := s.db.ExecContext(ctx, query); err != nil {
+		return fmt.Errorf("failed to create replay table: %w", err)
+	}
+	return nil
+}
+
+// Claim implements the ReplayStore interface
+func (s *SQLReplayStore) Claim(ctx context.Context, key string, lease time.Duration) (ReplayState, error) {
+	now := s.now()
+
+	// Remove the key if it expired, so that it can be recorded again
+	deleteQuery := fmt.Sprintf("DELETE FROM %s WHERE replay_key = %s AND expires_at <= %s",
+		s.table, s.placeholder(1), s.placeholder(2))
+	if _, err := s.db.ExecContext(ctx, deleteQuery, key, now.UnixMilli()); err != nil {
+		return ReplayNew, fmt.Errorf("failed to remove expired replay key: %w", err)
+	}
+
+	// The primary key makes the insert fail if the key is already recorded
+	insertQuery := fmt.Sprintf("INSERT INTO %s (replay_key, state, expires_at) VALUES (%s, %s, %s)",
+		s.table, s.placeholder(1), s.placeholder(2), s.placeholder(3))
+	if _, insertErr := s.db.ExecContext(ctx, insertQuery, key, int(ReplayInProgress), now.Add(lease).UnixMilli()); insertErr != nil {
+		// Tell a recorded key apart from other failures
+		var state int
+		stateQuery := fmt.Sprintf("SELECT state FROM %s WHERE replay_key = %s", s.table, s.placeholder(1))
+		if err := s.db.QueryRowContext(ctx, stateQuery, key).Scan(&state); err != nil {
+			return ReplayNew, fmt.Errorf("failed to record replay key: %w", insertErr)
+		}
+		return ReplayState(state), nil
+	}
+
+	return ReplayNew, nil
+}
+
+// Complete implements the ReplayStore interface
+func (s *SQLReplayStore) Complete(ctx context.Context, key string, ttl time.Duration) error {
+	expiresAt := s.now().Add(ttl).UnixMilli()
+
+	updateQuery := fmt.Sprintf("UPDATE %s SET state = %s, expires_at = %s WHERE replay_key = %s",
+		s.table, s.placeholder(1), s.placeholder(2), s.placeholder(3))
+	result, err := s.db.ExecContext(ctx, updateQuery, int(ReplayDone), expiresAt, key)
+	if err != nil {
+		return fmt.Errorf("failed to complete replay key: %w", err)
+	}
+	if n, err := result.RowsAffected(); err == nil && n > 0 {
+		return nil
+	}
+
+	// The key was removed, e.g. because its lease expired
+	insertQuery := fmt.Sprintf("INSERT INTO %s (replay_key, state, expires_at) VALUES (%s, %s, %s)",
+		s.table, s.placeholder(1), s.placeholder(2), s.placeholder(3))
+	if _, err := s.db.ExecContext(ctx, insertQuery, key, int(ReplayDone), expiresAt); err != nil {
+		return fmt.Errorf("failed to complete replay key: %w", err)
+	}
+	return nil
+}
+
+// Forget implements the ReplayStore interface
+func (s *SQLReplayStore) Forget(ctx context.Context, key string) error {
+	query := fmt.Sprintf("DELETE FROM %s WHERE replay_key = %s", s.table, s.placeholder(1))
+	if _, err := s.db.ExecContext(ctx, query, key); err != nil {
+		return fmt.Errorf("failed to forget replay key: %w", err)
+	}
+	return nil
+}
+
+// DeleteExpired removes the expired keys from the table
+func (s *SQLReplayStore) DeleteExpired(ctx context.Context) (int64, error) {
+	query := fmt.Sprintf("DELETE FROM %s WHERE expires_at <= %s", s.table, s.placeholder(1))
+	result, err := s.db.ExecContext(ctx, query, s.now().UnixMilli())
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete expired replay keys: %w", err)
+	}
+	return result.RowsAffected()
+}
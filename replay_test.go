@@ -0,0 +1,257 @@
+package irembopay
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"sync"
+	"testing"
+	"time"
+)
+
+// testReplayStore checks the states of keys in a replay store whose clock is
+// read from now
+func testReplayStore(t *testing.T, store ReplayStore, now *time.Time) {
+	t.Helper()
+	ctx := context.Background()
+
+	claim := func(key string, want ReplayState) {
+		t.Helper()
+		got, err := store.Claim(ctx, key, time.Minute)
+		if err != nil {
+			t.Fatalf("Claim(%s) returned error: %v", key, err)
+		}
+		if got != want {
+			t.Fatalf("Claim(%s) = %d, want %d", key, got, want)
+		}
+	}
+	complete := func(key string) {
+		t.Helper()
+		if err := store.Complete(ctx, key, time.Hour); err != nil {
+			t.Fatalf("Complete(%s) returned error: %v", key, err)
+		}
+	}
+
+	// A claimed key is in progress until it is completed
+	claim("a", ReplayNew)
+	claim("a", ReplayInProgress)
+	complete("a")
+	claim("a", ReplayDone)
+
+	// A forgotten key can be claimed again
+	if err := store.Forget(ctx, "a"); err != nil {
+		t.Fatalf("Forget returned error: %v", err)
+	}
+	claim("a", ReplayNew)
+	complete("a")
+
+	// The lease of a key that was never completed expires
+	claim("b", ReplayNew)
+	*now = now.Add(time.Minute)
+	claim("b", ReplayNew)
+	claim("a", ReplayDone)
+
+	// Completed keys expire after the TTL
+	*now = now.Add(time.Hour)
+	claim("a", ReplayNew)
+
+	// Completing a key whose lease expired records it again
+	*now = now.Add(time.Hour)
+	complete("b")
+	claim("b", ReplayDone)
+}
+
+func TestMemoryReplayStore(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	store := NewMemoryReplayStore(10)
+	store.now = func() time.Time { return now }
+
+	testReplayStore(t, store, &now)
+}
+
+func TestMemoryReplayStoreEvictsOldestKeys(t *testing.T) {
+	store := NewMemoryReplayStore(2)
+	ctx := context.Background()
+
+	for _, key := range []string{"a", "b", "c"} {
+		if _, err := store.Claim(ctx, key, time.Hour); err != nil {
+			t.Fatalf("Claim(%s) returned error: %v", key, err)
+		}
+	}
+	if got := store.Len(); got != 2 {
+		t.Errorf("Len() = %d, want 2", got)
+	}
+	if state, _ := store.Claim(ctx, "a", time.Hour); state != ReplayNew {
+		t.Errorf("Claim of an evicted key = %d, want ReplayNew", state)
+	}
+	if state, _ := store.Claim(ctx, "c", time.Hour); state != ReplayInProgress {
+		t.Errorf("Claim of a kept key = %d, want ReplayInProgress", state)
+	}
+}
+
+func TestSQLReplayStore(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		opts []SQLReplayStoreOption
+	}{
+		{name: "question mark placeholders"},
+		{name: "dollar placeholders", opts: []SQLReplayStoreOption{WithDollarPlaceholders()}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			db := sql.OpenDB(&fakeReplayDB{rows: make(map[string]fakeReplayRow)})
+			defer db.Close()
+
+			now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+			store, err := NewSQLReplayStore(db, "webhook_replays", tt.opts...)
+			if err != nil {
+				t.Fatalf("NewSQLReplayStore returned error: %v", err)
+			}
+			store.now = func() time.Time { return now }
+			if err := store.CreateTable(context.Background()); err != nil {
+				t.Fatalf("CreateTable returned error: %v", err)
+			}
+
+			testReplayStore(t, store, &now)
+
+			// The lease of a expired, b is kept for the TTL
+			now = now.Add(30 * time.Minute)
+			deleted, err := store.DeleteExpired(context.Background())
+			if err != nil {
+				t.Fatalf("DeleteExpired returned error: %v", err)
+			}
+			if deleted != 1 {
+				t.Errorf("DeleteExpired deleted %d keys, want 1", deleted)
+			}
+		})
+	}
+}
+
+func TestNewSQLReplayStoreRejectsInvalidTableName(t *testing.T) {
+	db := sql.OpenDB(&fakeReplayDB{rows: make(map[string]fakeReplayRow)})
+	defer db.Close()
+
+	if _, err := NewSQLReplayStore(db, "replays; DROP TABLE users"); err == nil {
+		t.Error("NewSQLReplayStore should reject a table name that is not an identifier")
+	}
+	if _, err := NewSQLReplayStore(nil, "replays"); err == nil {
+		t.Error("NewSQLReplayStore should reject a nil database")
+	}
+}
+
+// fakeReplayRow is a row of the fake replay table
+type fakeReplayRow struct {
+	state     int64
+	expiresAt int64
+}
+
+// fakeReplayDB is a database/sql driver understanding the queries of
+// SQLReplayStore, with a primary key on replay_key
+type fakeReplayDB struct {
+	mu   sync.Mutex
+	rows map[string]fakeReplayRow
+}
+
+func (db *fakeReplayDB) Connect(ctx context.Context) (driver.Conn, error) { return db, nil }
+func (db *fakeReplayDB) Driver() driver.Driver                            { return nil }
+func (db *fakeReplayDB) Close() error                                     { return nil }
+func (db *fakeReplayDB) Begin() (driver.Tx, error)                        { return nil, errors.New("not supported") }
+
+func (db *fakeReplayDB) Prepare(query string) (driver.Stmt, error) {
+	return nil, errors.New("not supported")
+}
+
+var (
+	dollarPlaceholder = regexp.MustCompile(`\$[0-9]+`)
+	createQuery       = regexp.MustCompile(`^CREATE TABLE IF NOT EXISTS webhook_replays \(`)
+	deleteKeyQuery    = regexp.MustCompile(`^DELETE FROM webhook_replays WHERE replay_key = \?$`)
+	deleteStaleQuery  = regexp.MustCompile(`^DELETE FROM webhook_replays WHERE replay_key = \? AND expires_at <= \?$`)
+	deleteAllQuery    = regexp.MustCompile(`^DELETE FROM webhook_replays WHERE expires_at <= \?$`)
+	insertQuery       = regexp.MustCompile(`^INSERT INTO webhook_replays \(replay_key, state, expires_at\) VALUES \(\?, \?, \?\)$`)
+	updateQuery       = regexp.MustCompile(`^UPDATE webhook_replays SET state = \?, expires_at = \? WHERE replay_key = \?$`)
+	selectQuery       = regexp.MustCompile(`^SELECT state FROM webhook_replays WHERE replay_key = \?$`)
+)
+
+func (db *fakeReplayDB) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	query = dollarPlaceholder.ReplaceAllString(query, "?")
+	switch {
+	case createQuery.MatchString(query):
+		return driver.RowsAffected(0), nil
+	case deleteKeyQuery.MatchString(query):
+		key := args[0].Value.(string)
+		if _, ok := db.rows[key]; !ok {
+			return driver.RowsAffected(0), nil
+		}
+		delete(db.rows, key)
+		return driver.RowsAffected(1), nil
+	case deleteStaleQuery.MatchString(query):
+		key, now := args[0].Value.(string), args[1].Value.(int64)
+		if row, ok := db.rows[key]; !ok || row.expiresAt > now {
+			return driver.RowsAffected(0), nil
+		}
+		delete(db.rows, key)
+		return driver.RowsAffected(1), nil
+	case deleteAllQuery.MatchString(query):
+		now := args[0].Value.(int64)
+		var n int64
+		for key, row := range db.rows {
+			if row.expiresAt <= now {
+				delete(db.rows, key)
+				n++
+			}
+		}
+		return driver.RowsAffected(n), nil
+	case insertQuery.MatchString(query):
+		key := args[0].Value.(string)
+		if _, ok := db.rows[key]; ok {
+			return nil, errors.New("UNIQUE constraint failed: webhook_replays.replay_key")
+		}
+		db.rows[key] = fakeReplayRow{state: args[1].Value.(int64), expiresAt: args[2].Value.(int64)}
+		return driver.RowsAffected(1), nil
+	case updateQuery.MatchString(query):
+		key := args[2].Value.(string)
+		if _, ok := db.rows[key]; !ok {
+			return driver.RowsAffected(0), nil
+		}
+		db.rows[key] = fakeReplayRow{state: args[0].Value.(int64), expiresAt: args[1].Value.(int64)}
+		return driver.RowsAffected(1), nil
+	}
+	return nil, fmt.Errorf("unexpected query: %s", query)
+}
+
+func (db *fakeReplayDB) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	query = dollarPlaceholder.ReplaceAllString(query, "?")
+	if !selectQuery.MatchString(query) {
+		return nil, fmt.Errorf("unexpected query: %s", query)
+	}
+	rows := &fakeReplayRows{}
+	if row, ok := db.rows[args[0].Value.(string)]; ok {
+		rows.states = []int64{row.state}
+	}
+	return rows, nil
+}
+
+// fakeReplayRows are the rows returned by a fakeReplayDB query
+type fakeReplayRows struct {
+	states []int64
+}
+
+func (r *fakeReplayRows) Columns() []string { return []string{"state"} }
+func (r *fakeReplayRows) Close() error      { return nil }
+
+func (r *fakeReplayRows) Next(dest []driver.Value) error {
+	if len(r.states) == 0 {
+		return io.EOF
+	}
+	dest[0], r.states = r.states[0], r.states[1:]
+	return nil
+}
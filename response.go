@@ -0,0 +1,76 @@
+package irembopay
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+)
+
+// requestIDHeaders are the headers that may carry the ID the API assigned to
+// a request, in order of preference
+var requestIDHeaders = []string{"X-Request-Id", "X-Correlation-Id", "Request-Id", "X-Amzn-RequestId"}
+
+// ResponseMeta describes the raw HTTP response of an API call. It is filled by
+// the service methods called with a context returned by WithResponse.
+type ResponseMeta struct {
+	StatusCode int           // HTTP status code, 0 if no response was received
+	Header     http.Header   // Response headers
+	Message    string        // The message field of the response body, if any
+	RequestID  string        // ID of the request assigned by the API, if any
+	Latency    time.Duration // Duration of the call, including retries
+	Attempts   int           // Number of attempts made
+	RawBody    []byte        // Undecoded response body
+}
+
+// responseMetaKey is the context key of the ResponseMeta to fill
+type responseMetaKey struct{}
+
+// WithResponse returns a context capturing the raw response of the API calls
+// made with it into meta. When a method makes several calls, such as Cancel or
+// ListAll, meta describes the last one.
+//
+//	var meta irembopay.ResponseMeta
+//	invoice, err := client.Invoice.Get(irembopay.WithResponse(ctx, &meta), invoiceNumber)
+//	log.Printf("request ID %s, HTTP %d", meta.RequestID, meta.StatusCode)
+func WithResponse(ctx context.Context, meta *ResponseMeta) context.Context {
+	return context.WithValue(ctx, responseMetaKey{}, meta)
+}
+
+// captureResponse fills the ResponseMeta of the context, if any
+func captureResponse(ctx context.Context, info *callInfo, latency time.Duration) {
+	meta, ok := ctx.Value(responseMetaKey{}).(*ResponseMeta)
+	if !ok || meta == nil {
+		return
+	}
+
+	*meta = ResponseMeta{
+		Latency:  latency,
+		Attempts: info.attempts,
+	}
+	if info.resp == nil {
+		return
+	}
+
+	meta.StatusCode = info.resp.StatusCode
+	meta.Header = info.resp.Header
+	meta.RequestID = requestID(info.resp.Header)
+	meta.RawBody = info.body
+
+	var body struct {
+		Message string `json:"message"`
+	}
+	if err := json.Unmarshal(info.body, &body); err == nil {
+		meta.Message = body.Message
+	}
+}
+
+// requestID returns the ID of the request assigned by the API, if any
+func requestID(header http.Header) string {
+	for _, name := range requestIDHeaders {
+		if id := header.Get(name); id != "" {
+			return id
+		}
+	}
+	return ""
+}
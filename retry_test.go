@@ -0,0 +1,243 @@
+package irembopay_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cruso003/irembopay"
+)
+
+// newTestClient creates a client sending its requests to an httptest server
+// running the handler
+func newTestClient(t *testing.T, handler http.Handler, opts ...irembopay.ConfigOption) *irembopay.IremboPay {
+	t.Helper()
+
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+
+	baseURL, err := url.Parse(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	config, err := irembopay.NewConfig(irembopay.Sandbox, "test-secret-key",
+		append([]irembopay.ConfigOption{irembopay.WithBaseURL(baseURL)}, opts...)...)
+	if err != nil {
+		t.Fatalf("NewConfig returned error: %v", err)
+	}
+	return irembopay.NewIremboPay(config)
+}
+
+// recorder records the requests received by a test server and answers them
+// with the responses of respond
+type recorder struct {
+	mu       sync.Mutex
+	requests []*http.Request
+	times    []time.Time
+	respond  func(attempt int, w http.ResponseWriter, r *http.Request)
+}
+
+func (rec *recorder) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	rec.mu.Lock()
+	rec.requests = append(rec.requests, r)
+	rec.times = append(rec.times, time.Now())
+	attempt := len(rec.requests)
+	rec.mu.Unlock()
+
+	rec.respond(attempt, w, r)
+}
+
+// count returns the number of requests received
+func (rec *recorder) count() int {
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	return len(rec.requests)
+}
+
+// writeJSON writes a JSON response
+func writeJSON(w http.ResponseWriter, statusCode int, body string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	w.Write([]byte(body))
+}
+
+const invoiceResponse = `{"success":true,"message":"Success","data":{"invoiceNumber":"880419623157","transactionId":"TST-001","paymentStatus":"NEW"}}`
+
+// invoiceRequest returns a valid invoice request
+func invoiceRequest() *irembopay.InvoiceRequest {
+	return &irembopay.InvoiceRequest{
+		TransactionID:            "TST-001",
+		PaymentAccountIdentifier: "TST-RWF",
+		PaymentItems:             []irembopay.PaymentItem{{Code: "PC-1", Quantity: 1, UnitAmount: 1000}},
+	}
+}
+
+// fastRetries returns a retry policy with short delays
+func fastRetries() irembopay.RetryPolicy {
+	policy := irembopay.DefaultRetryPolicy()
+	policy.BaseDelay = time.Millisecond
+	policy.MaxDelay = 10 * time.Millisecond
+	policy.Jitter = 0
+	return policy
+}
+
+func TestRetryGetOnServiceUnavailable(t *testing.T) {
+	rec := &recorder{respond: func(attempt int, w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusServiceUnavailable, `{"success":false,"message":"unavailable"}`)
+	}}
+	client := newTestClient(t, rec, irembopay.WithRetryPolicy(fastRetries()))
+
+	_, err := client.Invoice.Get(context.Background(), "880419623157")
+	if !irembopay.IsServerError(err) {
+		t.Fatalf("Get returned %v, want a server error", err)
+	}
+	if got := rec.count(); got != 3 {
+		t.Errorf("server received %d requests, want 3", got)
+	}
+}
+
+func TestRetryGetSucceedsAfterServiceUnavailable(t *testing.T) {
+	rec := &recorder{respond: func(attempt int, w http.ResponseWriter, r *http.Request) {
+		if attempt < 3 {
+			writeJSON(w, http.StatusServiceUnavailable, `{"success":false,"message":"unavailable"}`)
+			return
+		}
+		writeJSON(w, http.StatusOK, invoiceResponse)
+	}}
+	client := newTestClient(t, rec, irembopay.WithRetryPolicy(fastRetries()))
+
+	invoice, err := client.Invoice.Get(context.Background(), "880419623157")
+	if err != nil {
+		t.Fatalf("Get returned error: %v", err)
+	}
+	if invoice.InvoiceNumber != "880419623157" {
+		t.Errorf("InvoiceNumber = %q, want 880419623157", invoice.InvoiceNumber)
+	}
+	if got := rec.count(); got != 3 {
+		t.Errorf("server received %d requests, want 3", got)
+	}
+}
+
+func TestRetryPostWithoutIdempotencyKeyIsSentOnce(t *testing.T) {
+	rec := &recorder{respond: func(attempt int, w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusServiceUnavailable, `{"success":false,"message":"unavailable"}`)
+	}}
+	client := newTestClient(t, rec, irembopay.WithRetryPolicy(fastRetries()))
+
+	_, err := client.Invoice.Create(context.Background(), invoiceRequest())
+	if !irembopay.IsServerError(err) {
+		t.Fatalf("Create returned %v, want a server error", err)
+	}
+	if got := rec.count(); got != 1 {
+		t.Errorf("server received %d requests, want 1", got)
+	}
+}
+
+func TestRetryPostWithIdempotencyKey(t *testing.T) {
+	rec := &recorder{respond: func(attempt int, w http.ResponseWriter, r *http.Request) {
+		if attempt == 1 {
+			writeJSON(w, http.StatusServiceUnavailable, `{"success":false,"message":"unavailable"}`)
+			return
+		}
+		writeJSON(w, http.StatusCreated, invoiceResponse)
+	}}
+	client := newTestClient(t, rec, irembopay.WithRetryPolicy(fastRetries()))
+
+	_, err := client.Invoice.CreateWithIdempotency(context.Background(), invoiceRequest(), "key-1")
+	if err != nil {
+		t.Fatalf("CreateWithIdempotency returned error: %v", err)
+	}
+	if got := rec.count(); got != 2 {
+		t.Fatalf("server received %d requests, want 2", got)
+	}
+	for i, req := range rec.requests {
+		if key := req.Header.Get("X-Idempotency-Key"); key != "key-1" {
+			t.Errorf("request %d has idempotency key %q, want key-1", i+1, key)
+		}
+	}
+}
+
+func TestRetryHonorsRetryAfter(t *testing.T) {
+	rec := &recorder{respond: func(attempt int, w http.ResponseWriter, r *http.Request) {
+		if attempt == 1 {
+			w.Header().Set("Retry-After", "0")
+			writeJSON(w, http.StatusTooManyRequests, `{"success":false,"message":"slow down"}`)
+			return
+		}
+		writeJSON(w, http.StatusOK, invoiceResponse)
+	}}
+
+	// The backoff alone would wait an hour
+	policy := fastRetries()
+	policy.BaseDelay = time.Hour
+	policy.MaxDelay = 0
+	client := newTestClient(t, rec, irembopay.WithRetryPolicy(policy))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if _, err := client.Invoice.Get(ctx, "880419623157"); err != nil {
+		t.Fatalf("Get returned error: %v", err)
+	}
+	if got := rec.count(); got != 2 {
+		t.Errorf("server received %d requests, want 2", got)
+	}
+}
+
+func TestRetryAfterIsCappedByMaxDelay(t *testing.T) {
+	rec := &recorder{respond: func(attempt int, w http.ResponseWriter, r *http.Request) {
+		if attempt == 1 {
+			w.Header().Set("Retry-After", "3600")
+			writeJSON(w, http.StatusServiceUnavailable, `{"success":false,"message":"unavailable"}`)
+			return
+		}
+		writeJSON(w, http.StatusOK, invoiceResponse)
+	}}
+
+	policy := fastRetries()
+	policy.MaxDelay = 50 * time.Millisecond
+	client := newTestClient(t, rec, irembopay.WithRetryPolicy(policy))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if _, err := client.Invoice.Get(ctx, "880419623157"); err != nil {
+		t.Fatalf("Get returned error: %v", err)
+	}
+	if got := rec.count(); got != 2 {
+		t.Fatalf("server received %d requests, want 2", got)
+	}
+	if gap := rec.times[1].Sub(rec.times[0]); gap < policy.MaxDelay {
+		t.Errorf("retried after %v, want at least %v", gap, policy.MaxDelay)
+	}
+}
+
+func TestRetryStopsWhenContextIsCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	rec := &recorder{respond: func(attempt int, w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusServiceUnavailable, `{"success":false,"message":"unavailable"}`)
+		time.AfterFunc(20*time.Millisecond, cancel)
+	}}
+
+	policy := fastRetries()
+	policy.BaseDelay = time.Hour
+	policy.MaxDelay = 0
+	client := newTestClient(t, rec, irembopay.WithRetryPolicy(policy))
+
+	start := time.Now()
+	_, err := client.Invoice.Get(ctx, "880419623157")
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("Get returned %v, want context.Canceled", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("Get returned after %v, want the backoff to stop on cancellation", elapsed)
+	}
+	if got := rec.count(); got != 1 {
+		t.Errorf("server received %d requests, want 1", got)
+	}
+}
@@ -0,0 +1,146 @@
+package irembopay
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// SignWebhook returns the irembopay-signature header value for a payload sent at
+// the given time, in the format checked by VerifyWebhookSignature and
+// ValidateWebhookTimestamp: t=<milliseconds>, s=HMAC_SHA256(secret, t#payload)
+func SignWebhook(secret, payload string, t time.Time) string {
+	timestamp := strconv.FormatInt(t.UnixMilli(), 10)
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "#" + payload))
+
+	return fmt.Sprintf("t=%s, s=%s", timestamp, hex.EncodeToString(mac.Sum(nil)))
+}
+
+// NotificationBuilder builds payment notifications for tests and local development
+type NotificationBuilder struct {
+	notification PaymentNotification
+}
+
+// NewNotificationBuilder creates a builder for a paid mobile money notification
+// with realistic sandbox values
+func NewNotificationBuilder() *NotificationBuilder {
+	return &NotificationBuilder{
+		notification: PaymentNotification{
+			InvoiceNumber:     "880419623157",
+			TransactionID:     "TST-12345",
+			PaymentStatus:     PaymentStatusPaid,
+			PaymentReference:  "MTN-1234567890",
+			Amount:            2000,
+			Currency:          RWF,
+			PaymentMethod:     PaymentMethodMTNMomo,
+			PaidAt:            FormatTime(time.Now()),
+			PaymentAccountID:  "TST-RWF",
+			PaymentMerchantID: "TST-MERCHANT",
+		},
+	}
+}
+
+// ForInvoice copies the invoice number, transaction ID, amount, currency and
+// payment account of an invoice
+func (b *NotificationBuilder) ForInvoice(invoice *Invoice) *NotificationBuilder {
+	b.notification.InvoiceNumber = invoice.InvoiceNumber
+	b.notification.TransactionID = invoice.TransactionID
+	b.notification.Amount = invoice.Amount
+	b.notification.Currency = invoice.Currency
+	b.notification.PaymentAccountID = invoice.PaymentAccountIdentifier
+	if invoice.PaymentReference != "" {
+		b.notification.PaymentReference = invoice.PaymentReference
+	}
+	if invoice.PaymentMethod != "" {
+		b.notification.PaymentMethod = invoice.PaymentMethod
+	}
+	if invoice.PaidAt != "" {
+		b.notification.PaidAt = invoice.PaidAt
+	}
+	return b
+}
+
+// WithInvoiceNumber sets the invoice number
+func (b *NotificationBuilder) WithInvoiceNumber(invoiceNumber string) *NotificationBuilder {
+	b.notification.InvoiceNumber = invoiceNumber
+	return b
+}
+
+// WithTransactionID sets the transaction ID
+func (b *NotificationBuilder) WithTransactionID(transactionID string) *NotificationBuilder {
+	b.notification.TransactionID = transactionID
+	return b
+}
+
+// WithStatus sets the payment status
+func (b *NotificationBuilder) WithStatus(status PaymentStatus) *NotificationBuilder {
+	b.notification.PaymentStatus = status
+	return b
+}
+
+// WithPaymentReference sets the payment reference
+func (b *NotificationBuilder) WithPaymentReference(reference string) *NotificationBuilder {
+	b.notification.PaymentReference = reference
+	return b
+}
+
+// WithAmount sets the amount and its currency
+func (b *NotificationBuilder) WithAmount(amount Money) *NotificationBuilder {
+	b.notification.Amount = amount.Float64()
+	b.notification.Currency = amount.Currency
+	return b
+}
+
+// WithPaymentMethod sets the payment method
+func (b *NotificationBuilder) WithPaymentMethod(method PaymentMethod) *NotificationBuilder {
+	b.notification.PaymentMethod = method
+	return b
+}
+
+// WithPaidAt sets the payment date
+func (b *NotificationBuilder) WithPaidAt(paidAt time.Time) *NotificationBuilder {
+	b.notification.PaidAt = FormatTime(paidAt)
+	return b
+}
+
+// WithPaymentAccountID sets the payment account ID
+func (b *NotificationBuilder) WithPaymentAccountID(accountID string) *NotificationBuilder {
+	b.notification.PaymentAccountID = accountID
+	return b
+}
+
+// WithMerchantID sets the merchant ID
+func (b *NotificationBuilder) WithMerchantID(merchantID string) *NotificationBuilder {
+	b.notification.PaymentMerchantID = merchantID
+	return b
+}
+
+// Build returns a copy of the notification
+func (b *NotificationBuilder) Build() *PaymentNotification {
+	notification := b.notification
+	return &notification
+}
+
+// Payload returns the notification encoded as a webhook payload
+func (b *NotificationBuilder) Payload() (string, error) {
+	payload, err := json.Marshal(b.notification)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode notification: %w", err)
+	}
+	return string(payload), nil
+}
+
+// Sign returns the webhook payload and its irembopay-signature header value
+func (b *NotificationBuilder) Sign(secret string, t time.Time) (payload, signature string, err error) {
+	payload, err = b.Payload()
+	if err != nil {
+		return "", "", err
+	}
+	return payload, SignWebhook(secret, payload, t), nil
+}
@@ -0,0 +1,204 @@
+package irembopay
+
+import (
+	"errors"
+	"fmt"
+	"net/mail"
+	"strings"
+	"time"
+
+	"github.com/cruso003/irembopay/phone"
+)
+
+// FieldError describes a validation problem with a single request field
+type FieldError struct {
+	Field   string // JSON name of the field, e.g. paymentItems[0].quantity
+	Message string // Description of the problem
+}
+
+// Error implements the error interface
+func (e FieldError) Error() string {
+	return fmt.Sprintf("%s %s", e.Field, e.Message)
+}
+
+// ValidationError holds all the validation problems of a request
+type ValidationError struct {
+	Errors []FieldError
+}
+
+// Error implements the error interface
+func (e *ValidationError) Error() string {
+	messages := make([]string, len(e.Errors))
+	for i, fieldErr := range e.Errors {
+		messages[i] = fieldErr.Error()
+	}
+	return fmt.Sprintf("invalid request: %s", strings.Join(messages, "; "))
+}
+
+// Is reports whether the error matches ErrValidation
+func (e *ValidationError) Is(target error) bool {
+	return target == ErrValidation
+}
+
+// add records a problem with a field
+func (e *ValidationError) add(field, format string, args ...interface{}) {
+	e.Errors = append(e.Errors, FieldError{Field: field, Message: fmt.Sprintf(format, args...)})
+}
+
+// err returns the validation error, or nil if no problem was recorded
+func (e *ValidationError) err() error {
+	if len(e.Errors) == 0 {
+		return nil
+	}
+	return e
+}
+
+// validator is implemented by requests that can be validated before being sent
+type validator interface {
+	Validate() error
+}
+
+// validateRequest validates the request unless validation is disabled in the configuration
+func validateRequest(config *Config, req validator) error {
+	if config.DisableRequestValidation {
+		return nil
+	}
+	return req.Validate()
+}
+
+// Validate checks the invoice request and reports all invalid fields
+func (r *InvoiceRequest) Validate() error {
+	var v ValidationError
+	if r == nil {
+		v.add("request", "is required")
+		return v.err()
+	}
+
+	if r.TransactionID == "" {
+		v.add("transactionId", "is required")
+	}
+	if r.PaymentAccountIdentifier == "" {
+		v.add("paymentAccountIdentifier", "is required")
+	}
+	validatePaymentItems(&v, r.PaymentItems, true)
+	if r.ExpiryAt != "" {
+		validateExpiry(&v, r.ExpiryAt)
+	}
+	if r.Language != "" && !r.Language.IsValid() {
+		v.add("language", "must be one of EN, FR or RW")
+	}
+	if r.Customer != nil && r.Customer.Email != "" {
+		if _, err := mail.ParseAddress(r.Customer.Email); err != nil {
+			v.add("customer.email", "is not a valid email address")
+		}
+	}
+
+	return v.err()
+}
+
+// Validate checks the batch invoice request and reports all invalid fields
+func (r *BatchInvoiceRequest) Validate() error {
+	var v ValidationError
+	if r == nil {
+		v.add("request", "is required")
+		return v.err()
+	}
+
+	if r.TransactionID == "" {
+		v.add("transactionId", "is required")
+	}
+	if len(r.InvoiceNumbers) == 0 {
+		v.add("invoiceNumbers", "must not be empty")
+	}
+	seen := make(map[string]bool)
+	for i, number := range r.InvoiceNumbers {
+		field := fmt.Sprintf("invoiceNumbers[%d]", i)
+		switch {
+		case number == "":
+			v.add(field, "is required")
+		case seen[number]:
+			v.add(field, "duplicates invoice %s", number)
+		}
+		seen[number] = true
+	}
+
+	return v.err()
+}
+
+// Validate checks the update request and reports all invalid fields
+func (r *UpdateInvoiceRequest) Validate() error {
+	var v ValidationError
+	if r == nil {
+		v.add("request", "is required")
+		return v.err()
+	}
+
+	if r.ExpiryAt == "" && len(r.PaymentItems) == 0 {
+		v.add("request", "must update expiryAt or paymentItems")
+	}
+	if r.ExpiryAt != "" {
+		validateExpiry(&v, r.ExpiryAt)
+	}
+	validatePaymentItems(&v, r.PaymentItems, false)
+
+	return v.err()
+}
+
+// Validate checks the mobile money payment request and reports all invalid fields
+func (r *MomoPaymentRequest) Validate() error {
+	var v ValidationError
+	if r == nil {
+		v.add("request", "is required")
+		return v.err()
+	}
+
+	if r.AccountIdentifier == "" {
+		v.add("accountIdentifier", "is required")
+	}
+	if !r.PaymentProvider.IsValid() {
+		v.add("paymentProvider", "must be MTN or AIRTEL")
+	}
+	if r.InvoiceNumber == "" {
+		v.add("invoiceNumber", "is required")
+	}
+
+	return v.err()
+}
+
+// validatePaymentItems records the problems of a list of payment items
+func validatePaymentItems(v *ValidationError, items []PaymentItem, required bool) {
+	if required && len(items) == 0 {
+		v.add("paymentItems", "must not be empty")
+	}
+	for i, item := range items {
+		if item.Code == "" {
+			v.add(fmt.Sprintf("paymentItems[%d].code", i), "is required")
+		}
+		if item.Quantity <= 0 {
+			v.add(fmt.Sprintf("paymentItems[%d].quantity", i), "must be greater than 0")
+		}
+		if item.UnitAmount < 0 {
+			v.add(fmt.Sprintf("paymentItems[%d].unitAmount", i), "cannot be negative")
+		}
+	}
+}
+
+// validateExpiry records a problem if the expiry time is malformed or in the past
+func validateExpiry(v *ValidationError, expiryAt string) {
+	expiry, err := ParseTime(expiryAt)
+	if err != nil {
+		v.add("expiryAt", "must be an RFC3339 time")
+		return
+	}
+	if !expiry.After(time.Now()) {
+		v.add("expiryAt", "must be in the future")
+	}
+}
+
+// phoneErrorMessage describes a phone number parsing error as a validation message
+func phoneErrorMessage(err error) string {
+	if errors.Is(err, phone.ErrUnknownOperator) {
+		return "is not an MTN or Airtel number"
+	}
+	return "is not a valid Rwandan mobile number"
+}
@@ -0,0 +1,38 @@
+package irembopay_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cruso003/irembopay"
+)
+
+func TestInvoiceRequestValidateCustomer(t *testing.T) {
+	tests := []struct {
+		name     string
+		customer *irembopay.Customer
+		wantErr  bool
+	}{
+		{name: "no customer"},
+		{name: "Rwandan phone number", customer: &irembopay.Customer{PhoneNumber: "0781234567"}},
+		{name: "foreign phone number", customer: &irembopay.Customer{PhoneNumber: "+33 6 12 34 56 78"}},
+		{name: "valid email", customer: &irembopay.Customer{Email: "jane@example.com"}},
+		{name: "invalid email", customer: &irembopay.Customer{Email: "not-an-email"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := &irembopay.InvoiceRequest{
+				TransactionID:            "TST-001",
+				PaymentAccountIdentifier: "TST-EUR",
+				PaymentItems:             []irembopay.PaymentItem{{Code: "PC-1", Quantity: 1, UnitAmount: 25}},
+				ExpiryAt:                 irembopay.FormatTime(time.Now().Add(time.Hour)),
+				Customer:                 tt.customer,
+			}
+			err := req.Validate()
+			if tt.wantErr != (err != nil) {
+				t.Errorf("Validate() = %v, want error: %v", err, tt.wantErr)
+			}
+		})
+	}
+}
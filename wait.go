@@ -0,0 +1,168 @@
+package irembopay
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// WaitOptions configures InvoiceService.WaitForPayment
+type WaitOptions struct {
+	InitialInterval time.Duration // Delay before the first poll (default: 2s)
+	MaxInterval     time.Duration // Upper bound for the delay between polls (default: 30s)
+	Multiplier      float64       // Growth factor of the delay between polls (default: 1.5)
+
+	// Notifier resolves the wait as soon as a paid notification for the
+	// invoice is published, instead of waiting for the next poll (optional)
+	Notifier *PaymentNotifier
+}
+
+// withDefaults returns the options with default values for unset fields
+func (o WaitOptions) withDefaults() WaitOptions {
+	if o.InitialInterval <= 0 {
+		o.InitialInterval = 2 * time.Second
+	}
+	if o.MaxInterval <= 0 {
+		o.MaxInterval = 30 * time.Second
+	}
+	if o.MaxInterval < o.InitialInterval {
+		o.MaxInterval = o.InitialInterval
+	}
+	if o.Multiplier < 1 {
+		o.Multiplier = 1.5
+	}
+	return o
+}
+
+// WaitForPayment polls an invoice until it is paid. It returns ErrInvoiceExpired
+// as soon as the invoice expires unpaid, ErrInvoiceCancelled if it is cancelled,
+// and the context error if the context is done first. Network, rate limit and
+// server errors do not stop the wait.
+func (s *InvoiceService) WaitForPayment(ctx context.Context, invoiceNumber string, opts WaitOptions) (*Invoice, error) {
+	opts = opts.withDefaults()
+
+	var notifications <-chan *PaymentNotification
+	if opts.Notifier != nil {
+		var unsubscribe func()
+		notifications, unsubscribe = opts.Notifier.subscribe(invoiceNumber)
+		defer unsubscribe()
+	}
+
+	interval := opts.InitialInterval
+	for {
+		invoice, err := s.Get(ctx, invoiceNumber)
+		switch {
+		case err == nil:
+			if invoice.IsPaid() {
+				return invoice, nil
+			}
+			if invoice.IsCancelled() {
+				return invoice, fmt.Errorf("failed to wait for payment of invoice %s: %w", invoiceNumber, ErrInvoiceCancelled)
+			}
+			if expiry, ok := invoiceExpiry(invoice); ok && !time.Now().Before(expiry) {
+				return invoice, fmt.Errorf("failed to wait for payment of invoice %s: %w", invoiceNumber, ErrInvoiceExpired)
+			}
+		case !isTransientError(err) || ctx.Err() != nil:
+			return nil, fmt.Errorf("failed to wait for payment: %w", err)
+		}
+
+		// Do not sleep past the expiry of the invoice
+		delay := interval
+		if invoice != nil {
+			if expiry, ok := invoiceExpiry(invoice); ok {
+				if untilExpiry := time.Until(expiry); untilExpiry < delay {
+					delay = untilExpiry
+				}
+			}
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, fmt.Errorf("failed to wait for payment: %w", ctx.Err())
+		case <-notifications:
+			// Poll immediately to confirm the notification
+			timer.Stop()
+		case <-timer.C:
+			interval = time.Duration(float64(interval) * opts.Multiplier)
+			if interval > opts.MaxInterval {
+				interval = opts.MaxInterval
+			}
+		}
+	}
+}
+
+// invoiceExpiry returns the expiry time of an invoice, if any
+func invoiceExpiry(invoice *Invoice) (time.Time, bool) {
+	if invoice.ExpiryAt == "" {
+		return time.Time{}, false
+	}
+	expiry, err := ParseTime(invoice.ExpiryAt)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return expiry, true
+}
+
+// isTransientError checks if a failed request may succeed when repeated later
+func isTransientError(err error) bool {
+	return IsNetworkError(err) || IsRateLimitedError(err) || IsServerError(err) || IsCircuitOpenError(err)
+}
+
+// PaymentNotifier forwards paid webhook notifications to the WaitForPayment
+// calls waiting for their invoice. It is safe for concurrent use.
+type PaymentNotifier struct {
+	mu      sync.Mutex
+	waiters map[string]map[chan *PaymentNotification]struct{}
+}
+
+// NewPaymentNotifier creates a new payment notifier
+func NewPaymentNotifier() *PaymentNotifier {
+	return &PaymentNotifier{
+		waiters: make(map[string]map[chan *PaymentNotification]struct{}),
+	}
+}
+
+// Notify publishes a notification, typically from a webhook callback.
+// Notifications that do not report a payment are ignored.
+func (n *PaymentNotifier) Notify(notification *PaymentNotification) {
+	if notification == nil || !notification.IsPaid() {
+		return
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	for ch := range n.waiters[notification.InvoiceNumber] {
+		// Waiters only need one signal, so never block on a full channel
+		select {
+		case ch <- notification:
+		default:
+		}
+	}
+}
+
+// subscribe registers a waiter for an invoice and returns its channel and a
+// function that removes it
+func (n *PaymentNotifier) subscribe(invoiceNumber string) (<-chan *PaymentNotification, func()) {
+	ch := make(chan *PaymentNotification, 1)
+
+	n.mu.Lock()
+	if n.waiters[invoiceNumber] == nil {
+		n.waiters[invoiceNumber] = make(map[chan *PaymentNotification]struct{})
+	}
+	n.waiters[invoiceNumber][ch] = struct{}{}
+	n.mu.Unlock()
+
+	return ch, func() {
+		n.mu.Lock()
+		defer n.mu.Unlock()
+
+		delete(n.waiters[invoiceNumber], ch)
+		if len(n.waiters[invoiceNumber]) == 0 {
+			delete(n.waiters, invoiceNumber)
+		}
+	}
+}
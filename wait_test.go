@@ -0,0 +1,164 @@
+package irembopay_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/cruso003/irembopay"
+	"github.com/cruso003/irembopay/irembopaytest"
+)
+
+// newWaitTest starts a fake server and creates an invoice on it
+func newWaitTest(t *testing.T, expiry time.Duration) (*irembopaytest.Server, *irembopay.IremboPay, *irembopay.Invoice) {
+	t.Helper()
+
+	srv := irembopaytest.NewServer("test-secret-key")
+	t.Cleanup(srv.Close)
+
+	client, err := srv.NewClient()
+	if err != nil {
+		t.Fatalf("NewClient returned error: %v", err)
+	}
+	invoice, err := client.Invoice.CreateWithExpiry(context.Background(), invoiceRequest(), expiry)
+	if err != nil {
+		t.Fatalf("CreateWithExpiry returned error: %v", err)
+	}
+	return srv, client, invoice
+}
+
+// slowPolling returns wait options that poll once, then only after an hour
+func slowPolling(notifier *irembopay.PaymentNotifier) irembopay.WaitOptions {
+	return irembopay.WaitOptions{InitialInterval: time.Hour, Notifier: notifier}
+}
+
+func TestWaitForPaymentResolvesOnNotification(t *testing.T) {
+	srv, client, invoice := newWaitTest(t, time.Hour)
+
+	paidAt := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	srv.SetClock(func() time.Time { return paidAt })
+
+	notifier := irembopay.NewPaymentNotifier()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	done := make(chan struct{})
+	var paid *irembopay.Invoice
+	var err error
+	go func() {
+		defer close(done)
+		paid, err = client.Invoice.WaitForPayment(ctx, invoice.InvoiceNumber, slowPolling(notifier))
+	}()
+
+	// Notifications for other invoices and unpaid notifications are ignored
+	time.Sleep(50 * time.Millisecond)
+	notifier.Notify(&irembopay.PaymentNotification{InvoiceNumber: "880419623999", PaymentStatus: irembopay.PaymentStatusPaid})
+	notifier.Notify(&irembopay.PaymentNotification{InvoiceNumber: invoice.InvoiceNumber, PaymentStatus: irembopay.PaymentStatusNew})
+	select {
+	case <-done:
+		t.Fatalf("WaitForPayment returned before the invoice was paid: %v", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	notification, markErr := srv.MarkPaid(invoice.InvoiceNumber, irembopay.PaymentMethodMTNMomo)
+	if markErr != nil {
+		t.Fatalf("MarkPaid returned error: %v", markErr)
+	}
+	notifier.Notify(notification)
+	<-done
+
+	if err != nil {
+		t.Fatalf("WaitForPayment returned error: %v", err)
+	}
+	if !paid.IsPaid() || paid.PaidAt != irembopay.FormatTime(paidAt) {
+		t.Errorf("WaitForPayment returned status %s paid at %s, want PAID at %s",
+			paid.PaymentStatus, paid.PaidAt, irembopay.FormatTime(paidAt))
+	}
+}
+
+func TestWaitForPaymentReturnsPaidInvoice(t *testing.T) {
+	srv, client, invoice := newWaitTest(t, time.Hour)
+
+	if _, err := srv.MarkPaid(invoice.InvoiceNumber, irembopay.PaymentMethodAirtelMoney); err != nil {
+		t.Fatalf("MarkPaid returned error: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	paid, err := client.Invoice.WaitForPayment(ctx, invoice.InvoiceNumber, slowPolling(nil))
+	if err != nil {
+		t.Fatalf("WaitForPayment returned error: %v", err)
+	}
+	if paid.PaymentMethod != irembopay.PaymentMethodAirtelMoney {
+		t.Errorf("WaitForPayment returned payment method %s, want %s", paid.PaymentMethod, irembopay.PaymentMethodAirtelMoney)
+	}
+}
+
+func TestWaitForPaymentStopsAtExpiry(t *testing.T) {
+	// Expiry times have a precision of one second
+	srv, client, invoice := newWaitTest(t, 2*time.Second)
+
+	// The wait does not sleep for the poll interval past the expiry
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	expired, err := client.Invoice.WaitForPayment(ctx, invoice.InvoiceNumber, slowPolling(nil))
+	if !errors.Is(err, irembopay.ErrInvoiceExpired) {
+		t.Fatalf("WaitForPayment returned %v, want ErrInvoiceExpired", err)
+	}
+	if expired == nil || expired.InvoiceNumber != invoice.InvoiceNumber {
+		t.Errorf("WaitForPayment returned invoice %v, want %s", expired, invoice.InvoiceNumber)
+	}
+
+	// The server agrees that the invoice can no longer be paid
+	srv.SetClock(func() time.Time { return time.Now().Add(time.Minute) })
+	if _, err := srv.MarkPaid(invoice.InvoiceNumber, irembopay.PaymentMethodMTNMomo); err == nil {
+		t.Error("MarkPaid of an expired invoice succeeded")
+	}
+}
+
+func TestWaitForPaymentStopsWhenCancelled(t *testing.T) {
+	_, client, invoice := newWaitTest(t, time.Hour)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := client.Invoice.WaitForPayment(ctx, invoice.InvoiceNumber, irembopay.WaitOptions{
+			InitialInterval: 10 * time.Millisecond,
+			MaxInterval:     10 * time.Millisecond,
+		})
+		done <- err
+	}()
+
+	time.Sleep(50 * time.Millisecond)
+	if _, err := client.Invoice.Cancel(ctx, invoice.InvoiceNumber, "Order abandoned"); err != nil {
+		t.Fatalf("Cancel returned error: %v", err)
+	}
+	if err := <-done; !errors.Is(err, irembopay.ErrInvoiceCancelled) {
+		t.Errorf("WaitForPayment returned %v, want ErrInvoiceCancelled", err)
+	}
+}
+
+func TestWaitForPaymentStopsWithContext(t *testing.T) {
+	_, client, invoice := newWaitTest(t, time.Hour)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		_, err := client.Invoice.WaitForPayment(ctx, invoice.InvoiceNumber, slowPolling(irembopay.NewPaymentNotifier()))
+		done <- err
+	}()
+
+	time.Sleep(50 * time.Millisecond)
+	cancel()
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("WaitForPayment returned %v, want context.Canceled", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("WaitForPayment did not return after its context was cancelled")
+	}
+}
@@ -0,0 +1,270 @@
+package irembopay
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// SignatureHeader is the HTTP header carrying the webhook signature
+const SignatureHeader = "irembopay-signature"
+
+const (
+	// DefaultWebhookMaxBodySize is the default maximum size of a webhook body
+	DefaultWebhookMaxBodySize = 1 << 20
+	// DefaultWebhookTolerance is the default maximum age of a webhook notification
+	DefaultWebhookTolerance = 5 * time.Minute
+	// DefaultWebhookProcessingLease is the default time a delivery being
+	// processed holds back its retries
+	DefaultWebhookProcessingLease = 5 * time.Minute
+)
+
+// WebhookOptions configures the webhook handler
+type WebhookOptions struct {
+	// OnNotification is called with every verified notification (required).
+	// Returning an error makes the handler answer with a 5xx status code so that
+	// IremboPay retries the delivery, unless the error is a *WebhookError.
+	OnNotification func(ctx context.Context, notification *PaymentNotification) error
+
+	// OnError is called when a request is rejected (optional)
+	OnError func(r *http.Request, err error)
+
+	// OnPreviousSecretKey is called when a notification was signed with a previous
+	// secret key, with the index of that key (optional). It helps to monitor when
+	// the old key can be retired after a rotation.
+	OnPreviousSecretKey func(r *http.Request, keyIndex int)
+
+	// OnDuplicate is called instead of OnNotification when a delivery was already
+	// processed, as detected by the configured ReplayStore (optional).
+	// Duplicates are acknowledged with HTTP 200 so that they are not retried.
+	// A delivery that is still being processed is answered with HTTP 503, so that
+	// IremboPay retries it in case the first attempt fails.
+	OnDuplicate func(ctx context.Context, notification *PaymentNotification)
+
+	MaxBodySize int64         // Maximum body size in bytes (default: 1 MiB)
+	Tolerance   time.Duration // Maximum age of a notification (default: 5 minutes, at most the replay TTL)
+
+	// ProcessingLease is the time a delivery being processed holds back its
+	// retries, after which a retry is processed again even if the first attempt
+	// never finished, e.g. because the process crashed (default: 5 minutes)
+	ProcessingLease time.Duration
+}
+
+// WebhookError is returned by a notification callback to choose the HTTP status
+// code sent back to IremboPay, e.g. a 4xx code to stop further delivery attempts
+type WebhookError struct {
+	StatusCode int
+	Err        error
+}
+
+// Error implements the error interface
+func (e *WebhookError) Error() string {
+	return fmt.Sprintf("webhook error (HTTP %d): %v", e.StatusCode, e.Err)
+}
+
+// Unwrap returns the underlying error
+func (e *WebhookError) Unwrap() error {
+	return e.Err
+}
+
+// NewWebhookError creates a new WebhookError
+func NewWebhookError(statusCode int, err error) *WebhookError {
+	return &WebhookError{
+		StatusCode: statusCode,
+		Err:        err,
+	}
+}
+
+// WebhookHandler returns an http.Handler that verifies webhook notifications
+// and passes them to the OnNotification callback
+func (s *PaymentService) WebhookHandler(opts WebhookOptions) http.Handler {
+	if opts.OnNotification == nil {
+		panic("irembopay: WebhookOptions.OnNotification is required")
+	}
+	if opts.MaxBodySize <= 0 {
+		opts.MaxBodySize = DefaultWebhookMaxBodySize
+	}
+	if opts.Tolerance <= 0 {
+		opts.Tolerance = DefaultWebhookTolerance
+	}
+	if s.config.ReplayStore != nil && opts.Tolerance > s.replayTTL() {
+		// Older deliveries would no longer be detected as duplicates
+		opts.Tolerance = s.replayTTL()
+	}
+	if opts.ProcessingLease <= 0 {
+		opts.ProcessingLease = DefaultWebhookProcessingLease
+	}
+
+	tracer := s.config.tracer()
+	meter := s.config.meter()
+	deliveries := meter.Counter(MetricWebhooks, "Number of IremboPay webhook deliveries")
+	duration := meter.Histogram(MetricWebhookDuration, "Duration of IremboPay webhook handling", "s")
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, span := tracer.Start(r.Context(), "irembopay webhook")
+		r = r.WithContext(ctx)
+		start := time.Now()
+
+		// Record the outcome of the delivery once it is handled
+		outcome, responseCode := "accepted", http.StatusOK
+		var invoiceNumber string
+		defer func() {
+			attrs := []Attribute{
+				{Key: AttrWebhookOutcome, Value: outcome},
+				{Key: AttrStatusCode, Value: responseCode},
+			}
+			deliveries.Add(ctx, 1, attrs...)
+			duration.Record(ctx, time.Since(start).Seconds(), attrs...)
+			if invoiceNumber != "" {
+				attrs = append(attrs, Attribute{Key: AttrInvoiceNumber, Value: invoiceNumber})
+			}
+			span.SetAttributes(attrs...)
+			span.End()
+		}()
+
+		var body []byte
+		reject := func(code int, err error) {
+			outcome, responseCode = "rejected", code
+			span.RecordError(err)
+			s.logWebhook(r, body, slog.LevelWarn, "irembopay webhook rejected",
+				slog.Int("status", code), slog.String("error", err.Error()))
+			if opts.OnError != nil {
+				opts.OnError(r, err)
+			}
+			http.Error(w, http.StatusText(code), code)
+		}
+
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			reject(http.StatusMethodNotAllowed, fmt.Errorf("method not allowed: %s", r.Method))
+			return
+		}
+
+		// Read the request body
+		var err error
+		body, err = io.ReadAll(http.MaxBytesReader(w, r.Body, opts.MaxBodySize))
+		if err != nil {
+			var maxBytesErr *http.MaxBytesError
+			if errors.As(err, &maxBytesErr) {
+				reject(http.StatusRequestEntityTooLarge, fmt.Errorf("request body too large: %w", err))
+				return
+			}
+			reject(http.StatusBadRequest, fmt.Errorf("failed to read request body: %w", err))
+			return
+		}
+
+		// Get the signature from the header
+		signature := r.Header.Get(SignatureHeader)
+		if signature == "" {
+			reject(http.StatusUnauthorized, fmt.Errorf("missing signature header"))
+			return
+		}
+
+		// Validate the timestamp (prevent replay attacks)
+		valid, err := s.ValidateWebhookTimestamp(signature, opts.Tolerance)
+		if err != nil {
+			reject(http.StatusBadRequest, fmt.Errorf("failed to validate timestamp: %w", err))
+			return
+		}
+		if !valid {
+			reject(http.StatusUnauthorized, fmt.Errorf("timestamp is too old"))
+			return
+		}
+
+		// Verify the signature
+		payload := string(body)
+		keyIndex, valid, err := s.MatchWebhookSignature(signature, payload)
+		if err != nil {
+			reject(http.StatusBadRequest, fmt.Errorf("failed to verify signature: %w", err))
+			return
+		}
+		if !valid {
+			reject(http.StatusUnauthorized, fmt.Errorf("invalid signature"))
+			return
+		}
+		if keyIndex > 0 && opts.OnPreviousSecretKey != nil {
+			opts.OnPreviousSecretKey(r, keyIndex)
+		}
+
+		// Parse the notification
+		notification, err := s.ParseNotification(payload)
+		if err != nil {
+			reject(http.StatusBadRequest, err)
+			return
+		}
+
+		invoiceNumber = notification.InvoiceNumber
+
+		// Skip deliveries that were already processed, and hold back retries of
+		// deliveries that are still being processed
+		state, err := s.claimReplay(r.Context(), signature, opts.ProcessingLease)
+		if err != nil {
+			reject(http.StatusInternalServerError, err)
+			return
+		}
+		switch state {
+		case ReplayInProgress:
+			reject(http.StatusServiceUnavailable, ErrNotificationInProgress)
+			return
+		case ReplayDone:
+			outcome = "duplicate"
+			s.logWebhook(r, body, slog.LevelInfo, "irembopay webhook duplicate",
+				slog.String("invoice_number", notification.InvoiceNumber))
+			if opts.OnDuplicate != nil {
+				opts.OnDuplicate(r.Context(), notification)
+			}
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+
+		// Process the notification
+		if err := opts.OnNotification(r.Context(), notification); err != nil {
+			statusCode := http.StatusInternalServerError
+			var webhookErr *WebhookError
+			if errors.As(err, &webhookErr) {
+				statusCode = webhookErr.StatusCode
+			}
+
+			// Accept the delivery again when it is retried
+			if releaseErr := s.ReleaseWebhook(r.Context(), signature); releaseErr != nil {
+				err = errors.Join(err, releaseErr)
+			}
+			reject(statusCode, fmt.Errorf("failed to process notification: %w", err))
+			return
+		}
+
+		// Only now are retries acknowledged as duplicates
+		if err := s.completeReplay(r.Context(), signature); err != nil {
+			s.logWebhook(r, body, slog.LevelWarn, "irembopay webhook not recorded as processed",
+				slog.String("invoice_number", notification.InvoiceNumber), slog.String("error", err.Error()))
+		}
+
+		s.logWebhook(r, body, slog.LevelInfo, "irembopay webhook accepted",
+			slog.String("invoice_number", notification.InvoiceNumber),
+			slog.String("payment_status", string(notification.PaymentStatus)))
+		w.WriteHeader(http.StatusOK)
+	})
+}
+
+// logWebhook logs a webhook delivery with its signature redacted according to the redaction policy
+func (s *PaymentService) logWebhook(r *http.Request, body []byte, level slog.Level, msg string, attrs ...slog.Attr) {
+	logger := s.config.Logger
+	if logger == nil {
+		return
+	}
+
+	policy := s.config.redaction()
+	attrs = append(attrs, slog.String("signature", policy.header(SignatureHeader, r.Header.Get(SignatureHeader))))
+	if policy.LogHeaders {
+		attrs = append(attrs, policy.headersAttr("headers", r.Header))
+	}
+	if policy.LogBodies && len(body) > 0 {
+		attrs = append(attrs, slog.String("body", policy.body(body)))
+	}
+
+	logger.LogAttrs(r.Context(), level, msg, attrs...)
+}
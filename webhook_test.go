@@ -0,0 +1,263 @@
+package irembopay_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/cruso003/irembopay"
+)
+
+const webhookSecret = "test-secret-key"
+
+// newWebhookClient creates a client for handling webhooks signed with webhookSecret
+func newWebhookClient(t *testing.T, opts ...irembopay.ConfigOption) *irembopay.IremboPay {
+	t.Helper()
+
+	config, err := irembopay.NewConfig(irembopay.Sandbox, webhookSecret, opts...)
+	if err != nil {
+		t.Fatalf("NewConfig returned error: %v", err)
+	}
+	return irembopay.NewIremboPay(config)
+}
+
+// notificationPayload returns the payload of a paid notification
+func notificationPayload(t *testing.T) string {
+	t.Helper()
+
+	payload, err := irembopay.NewNotificationBuilder().Payload()
+	if err != nil {
+		t.Fatalf("Payload returned error: %v", err)
+	}
+	return payload
+}
+
+// deliver sends a webhook delivery to the handler and returns the status code
+func deliver(handler http.Handler, payload, signature string) int {
+	req := httptest.NewRequest(http.MethodPost, "/webhooks/irembopay", strings.NewReader(payload))
+	if signature != "" {
+		req.Header.Set(irembopay.SignatureHeader, signature)
+	}
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	return rec.Code
+}
+
+func TestWebhookHandlerDuplicates(t *testing.T) {
+	client := newWebhookClient(t, irembopay.WithReplayStore(irembopay.NewMemoryReplayStore(10), time.Hour))
+
+	var processed, duplicates atomic.Int32
+	var fail atomic.Bool
+	handler := client.Payment.WebhookHandler(irembopay.WebhookOptions{
+		OnNotification: func(ctx context.Context, n *irembopay.PaymentNotification) error {
+			processed.Add(1)
+			if fail.Load() {
+				return errors.New("database unavailable")
+			}
+			return nil
+		},
+		OnDuplicate: func(ctx context.Context, n *irembopay.PaymentNotification) {
+			duplicates.Add(1)
+		},
+	})
+
+	payload := notificationPayload(t)
+	signature := irembopay.SignWebhook(webhookSecret, payload, time.Now())
+
+	// A failed delivery is processed again when it is retried
+	fail.Store(true)
+	if code := deliver(handler, payload, signature); code != http.StatusInternalServerError {
+		t.Fatalf("failed delivery answered %d, want 500", code)
+	}
+	fail.Store(false)
+	if code := deliver(handler, payload, signature); code != http.StatusOK {
+		t.Fatalf("retried delivery answered %d, want 200", code)
+	}
+
+	// A processed delivery is acknowledged without being processed again
+	if code := deliver(handler, payload, signature); code != http.StatusOK {
+		t.Fatalf("duplicate delivery answered %d, want 200", code)
+	}
+	if processed.Load() != 2 || duplicates.Load() != 1 {
+		t.Errorf("%d deliveries processed and %d duplicates, want 2 and 1", processed.Load(), duplicates.Load())
+	}
+}
+
+func TestWebhookHandlerRetryWhileProcessing(t *testing.T) {
+	client := newWebhookClient(t, irembopay.WithReplayStore(irembopay.NewMemoryReplayStore(10), time.Hour))
+
+	started := make(chan struct{})
+	finish := make(chan error)
+	var processed, duplicates atomic.Int32
+	handler := client.Payment.WebhookHandler(irembopay.WebhookOptions{
+		OnNotification: func(ctx context.Context, n *irembopay.PaymentNotification) error {
+			if processed.Add(1) == 1 {
+				close(started)
+				return <-finish
+			}
+			return nil
+		},
+		OnDuplicate: func(ctx context.Context, n *irembopay.PaymentNotification) {
+			duplicates.Add(1)
+		},
+	})
+
+	payload := notificationPayload(t)
+	signature := irembopay.SignWebhook(webhookSecret, payload, time.Now())
+
+	first := make(chan int)
+	go func() { first <- deliver(handler, payload, signature) }()
+	<-started
+
+	// A retry while the first attempt is running must be retried again later
+	if code := deliver(handler, payload, signature); code != http.StatusServiceUnavailable {
+		t.Fatalf("retry during processing answered %d, want 503", code)
+	}
+
+	// The first attempt fails, so the next retry is processed
+	finish <- errors.New("timeout")
+	if code := <-first; code != http.StatusInternalServerError {
+		t.Fatalf("first attempt answered %d, want 500", code)
+	}
+	if code := deliver(handler, payload, signature); code != http.StatusOK {
+		t.Fatalf("retry after a failure answered %d, want 200", code)
+	}
+	if processed.Load() != 2 || duplicates.Load() != 0 {
+		t.Errorf("%d deliveries processed and %d duplicates, want 2 and 0", processed.Load(), duplicates.Load())
+	}
+}
+
+func TestWebhookHandlerToleranceIsCappedByReplayTTL(t *testing.T) {
+	client := newWebhookClient(t, irembopay.WithReplayStore(irembopay.NewMemoryReplayStore(10), time.Minute))
+	handler := client.Payment.WebhookHandler(irembopay.WebhookOptions{
+		OnNotification: func(ctx context.Context, n *irembopay.PaymentNotification) error { return nil },
+		Tolerance:      time.Hour,
+	})
+
+	payload := notificationPayload(t)
+	signature := irembopay.SignWebhook(webhookSecret, payload, time.Now().Add(-2*time.Minute))
+	if code := deliver(handler, payload, signature); code != http.StatusUnauthorized {
+		t.Errorf("delivery older than the replay TTL answered %d, want 401", code)
+	}
+}
+
+func TestHandleWebhookReplays(t *testing.T) {
+	client := newWebhookClient(t, irembopay.WithReplayStore(irembopay.NewMemoryReplayStore(1), time.Hour))
+	payload := notificationPayload(t)
+
+	signature := irembopay.SignWebhook(webhookSecret, payload, time.Now())
+	if _, err := client.Payment.HandleWebhook(signature, payload); err != nil {
+		t.Fatalf("HandleWebhook returned error: %v", err)
+	}
+	if _, err := client.Payment.HandleWebhook(signature, payload); !irembopay.IsDuplicateNotificationError(err) {
+		t.Errorf("repeated HandleWebhook returned %v, want ErrDuplicateNotification", err)
+	}
+
+	// A released delivery is accepted again
+	if err := client.Payment.ReleaseWebhook(context.Background(), signature); err != nil {
+		t.Fatalf("ReleaseWebhook returned error: %v", err)
+	}
+	if _, err := client.Payment.HandleWebhook(signature, payload); err != nil {
+		t.Errorf("HandleWebhook after ReleaseWebhook returned error: %v", err)
+	}
+
+	// A delivery older than the replay TTL is rejected, since the store may
+	// have forgotten it
+	old := irembopay.SignWebhook(webhookSecret, payload, time.Now().Add(-2*time.Hour))
+	if _, err := client.Payment.HandleWebhook(old, payload); err == nil {
+		t.Error("HandleWebhook accepted a delivery older than the replay TTL")
+	}
+}
+
+func TestWebhookHandlerStatusCodes(t *testing.T) {
+	payload := notificationPayload(t)
+	valid := irembopay.SignWebhook(webhookSecret, payload, time.Now())
+
+	tests := []struct {
+		name      string
+		method    string
+		body      string
+		signature string
+		callback  error
+		want      int
+	}{
+		{name: "accepted", body: payload, signature: valid, want: http.StatusOK},
+		{name: "wrong method", method: http.MethodGet, signature: valid, want: http.StatusMethodNotAllowed},
+		{name: "body too large", body: strings.Repeat("x", 1025), signature: valid, want: http.StatusRequestEntityTooLarge},
+		{name: "missing signature", body: payload, want: http.StatusUnauthorized},
+		{name: "signature of another key", body: payload, signature: irembopay.SignWebhook("other-key", payload, time.Now()), want: http.StatusUnauthorized},
+		{name: "signature of another payload", body: payload, signature: irembopay.SignWebhook(webhookSecret, payload+" ", time.Now()), want: http.StatusUnauthorized},
+		{name: "malformed signature", body: payload, signature: "garbage", want: http.StatusBadRequest},
+		{name: "stale timestamp", body: payload, signature: irembopay.SignWebhook(webhookSecret, payload, time.Now().Add(-time.Hour)), want: http.StatusUnauthorized},
+		{name: "malformed payload", body: "{", signature: irembopay.SignWebhook(webhookSecret, "{", time.Now()), want: http.StatusBadRequest},
+		{name: "callback error", body: payload, signature: valid, callback: errors.New("database unavailable"), want: http.StatusInternalServerError},
+		{name: "callback webhook error", body: payload, signature: valid, callback: irembopay.NewWebhookError(http.StatusUnprocessableEntity, errors.New("unknown invoice")), want: http.StatusUnprocessableEntity},
+		{name: "wrapped webhook error", body: payload, signature: valid, callback: fmt.Errorf("processing: %w", irembopay.NewWebhookError(http.StatusGone, errors.New("invoice deleted"))), want: http.StatusGone},
+	}
+
+	client := newWebhookClient(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var called bool
+			var rejected error
+			handler := client.Payment.WebhookHandler(irembopay.WebhookOptions{
+				OnNotification: func(ctx context.Context, n *irembopay.PaymentNotification) error {
+					called = true
+					return tt.callback
+				},
+				OnError: func(r *http.Request, err error) {
+					rejected = err
+				},
+				MaxBodySize: 1024,
+			})
+
+			method := tt.method
+			if method == "" {
+				method = http.MethodPost
+			}
+			req := httptest.NewRequest(method, "/webhooks/irembopay", strings.NewReader(tt.body))
+			if tt.signature != "" {
+				req.Header.Set(irembopay.SignatureHeader, tt.signature)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, want %d", rec.Code, tt.want)
+			}
+			if wantCalled := tt.want == http.StatusOK || tt.callback != nil; called != wantCalled {
+				t.Errorf("OnNotification called: %v, want %v", called, wantCalled)
+			}
+			if (tt.want != http.StatusOK) != (rejected != nil) {
+				t.Errorf("OnError called with %v for status %d", rejected, rec.Code)
+			}
+			if tt.want == http.StatusMethodNotAllowed && rec.Header().Get("Allow") != http.MethodPost {
+				t.Errorf("Allow = %q, want POST", rec.Header().Get("Allow"))
+			}
+		})
+	}
+}
+
+func TestWebhookHandlerPreviousSecretKey(t *testing.T) {
+	client := newWebhookClient(t, irembopay.WithPreviousSecretKeys("old-key"))
+
+	keyIndex := -1
+	handler := client.Payment.WebhookHandler(irembopay.WebhookOptions{
+		OnNotification:      func(ctx context.Context, n *irembopay.PaymentNotification) error { return nil },
+		OnPreviousSecretKey: func(r *http.Request, i int) { keyIndex = i },
+	})
+
+	payload := notificationPayload(t)
+	if code := deliver(handler, payload, irembopay.SignWebhook("old-key", payload, time.Now())); code != http.StatusOK {
+		t.Fatalf("delivery signed with the previous key answered %d, want 200", code)
+	}
+	if keyIndex != 1 {
+		t.Errorf("OnPreviousSecretKey called with %d, want 1", keyIndex)
+	}
+}